@@ -0,0 +1,122 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// DefaultMaxParams is the number of bind parameters used per
+// statement by InsertBatch when no limit is given. It is the lowest
+// limit of the common databases, that of SQLite before 3.32.
+const DefaultMaxParams = 999
+
+// TxBeginner is an interface with just *sql.DB.BeginTx behaviour.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// InsertBatch inserts values like Insert, but splits them over as
+// many statements as needed to use at most maxParams bind parameters
+// in each. If maxParams is 0, DefaultMaxParams is used. The total
+// number of rows affected is returned.
+//
+//   n, err := InsertBatch(db, "X", values, 65535)
+//
+// Statements are executed in order, and an error stops any further
+// statements from being executed. Use InsertBatchTx so that no
+// values are inserted if one of the statements fails.
+func InsertBatch(db Executor, table string, values interface{}, maxParams int) (int64, error) {
+	return insertBatch(configOf(db), table, values, maxParams, func(i *preInsert) (sql.Result, error) {
+		return db.Exec(i.statement, i.args...)
+	})
+}
+
+// InsertBatchContext is like InsertBatch, but executes the statements
+// with a context.
+func InsertBatchContext(ctx context.Context, db ExecutorContext, table string, values interface{}, maxParams int) (int64, error) {
+	return insertBatch(configOf(db), table, values, maxParams, func(i *preInsert) (sql.Result, error) {
+		return db.ExecContext(ctx, i.statement, i.args...)
+	})
+}
+
+// InsertBatchTx is like InsertBatchContext, but executes all of the
+// statements in a single transaction, which is rolled back if any of
+// them fail. With a DB, use DB.InsertBatchTx instead, so that the
+// DB's options are kept.
+func InsertBatchTx(ctx context.Context, db TxBeginner, table string, values interface{}, maxParams int) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	n, err := InsertBatchContext(ctx, tx, table, values, maxParams)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+func insertBatch(c config, table string, values interface{}, maxParams int, exec func(*preInsert) (sql.Result, error)) (int64, error) {
+	if maxParams <= 0 {
+		maxParams = DefaultMaxParams
+	}
+
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice {
+		// A single value always fits in one statement, if at all
+		i, err := insert(c, table, values)
+		if err != nil {
+			return 0, err
+		}
+		res, err := exec(i)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+
+	if v.Len() < 1 {
+		return 0, fmt.Errorf("no values given")
+	}
+	var columns int
+	switch t := v.Type().Elem(); {
+	case isMap(t):
+		columns = v.Index(0).Len()
+	case t.Kind() == reflect.Struct:
+		names, _ := taggedFields(c, t, "insert")
+		columns = len(names)
+	default:
+		return 0, fmt.Errorf("values must be struct, map or slice of them, not: %v", t)
+	}
+	if columns < 1 {
+		return 0, fmt.Errorf("no columns available for insert")
+	}
+	size := maxParams / columns
+	if size < 1 {
+		return 0, fmt.Errorf("%d columns exceed the limit of %d parameters", columns, maxParams)
+	}
+
+	var total int64
+	for start := 0; start < v.Len(); start += size {
+		end := start + size
+		if end > v.Len() {
+			end = v.Len()
+		}
+		i, err := insert(c, table, v.Slice(start, end).Interface())
+		if err != nil {
+			return total, err
+		}
+		res, err := exec(i)
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
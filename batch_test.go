@@ -0,0 +1,100 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+type countingExecutor struct {
+	Executor
+	n int
+}
+
+func (c *countingExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	c.n++
+	return c.Executor.Exec(query, args...)
+}
+
+func TestInsertBatch(t *testing.T) {
+	type row struct {
+		A int    `sql:"a"`
+		B string `sql:"b"`
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`create table T(a int unique, b text)`); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []row
+	for i := 0; i < 10; i++ {
+		rows = append(rows, row{i, "x"})
+	}
+
+	count := func(t *testing.T) int {
+		var n int
+		if err := Scan(&n, db, `select count(*) from T`); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	t.Run("values are split over statements", func(t *testing.T) {
+		c := &countingExecutor{Executor: db}
+		n, err := InsertBatch(c, "T", rows, 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 10 {
+			t.Fatalf("expected 10 rows affected, got: %d", n)
+		}
+		if c.n != 4 {
+			t.Fatalf("expected 4 statements, got: %d", c.n)
+		}
+		if count(t) != 10 {
+			t.Fatalf("expected 10 rows, got: %d", count(t))
+		}
+	})
+
+	t.Run("too many columns for limit fails", func(t *testing.T) {
+		if _, err := InsertBatch(db, "T", rows, 1); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("non-struct elements fail like insert", func(t *testing.T) {
+		for _, values := range []interface{}{[]*row{{1, "x"}}, []int{1}} {
+			_, expect := Insert(db, "T", values)
+			_, err := InsertBatch(db, "T", values, 0)
+			if err == nil || expect == nil || err.Error() != expect.Error() {
+				t.Fatalf("expected error %v, got: %v", expect, err)
+			}
+		}
+	})
+
+	t.Run("failed transaction inserts nothing", func(t *testing.T) {
+		if _, err := db.Exec(`delete from T`); err != nil {
+			t.Fatal(err)
+		}
+		dup := append(rows[:0:0], rows...)
+		dup = append(dup, rows[0])
+		if _, err := InsertBatchTx(context.Background(), db, "T", dup, 6); err == nil {
+			t.Fatal("expected error")
+		}
+		if count(t) != 0 {
+			t.Fatalf("expected 0 rows, got: %d", count(t))
+		}
+		n, err := InsertBatchTx(context.Background(), db, "T", rows, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 10 || count(t) != 10 {
+			t.Fatalf("expected 10 rows, got: %d", count(t))
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package sqlh
+
+// Statement is an SQL statement with its arguments, as built by
+// BuildInsert or BuildUpdate. It can be run with
+//
+//   res, err := db.Exec(s.SQL, s.Args...)
+type Statement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// BuildInsert returns the statement Insert would run, without
+// running it.
+//
+//   s, err := BuildInsert("X", val{1, "test"})
+//   // = Statement{`insert into X(id, b) values($1, $2)`, []interface{}{1, "test"}}
+func BuildInsert(table string, values interface{}) (Statement, error) {
+	return buildInsert(defaults(), table, values)
+}
+
+// BuildUpsert returns the statement Upsert would run, without running
+// it.
+func BuildUpsert(table string, values interface{}, conflict ...string) (Statement, error) {
+	return buildUpsert(defaults(), table, values, conflict)
+}
+
+// BuildUpdate returns the statement Update would run, without running
+// it.
+func BuildUpdate(table string, value interface{}, where string, args ...interface{}) (Statement, error) {
+	return buildUpdateStatement(defaults(), table, value, where, args...)
+}
+
+// BuildInsert is like the package level BuildInsert, using the
+// options of db.
+func (db *DB) BuildInsert(table string, values interface{}) (Statement, error) {
+	return buildInsert(db.config(), table, values)
+}
+
+// BuildUpsert is like the package level BuildUpsert, using the
+// options of db.
+func (db *DB) BuildUpsert(table string, values interface{}, conflict ...string) (Statement, error) {
+	return buildUpsert(db.config(), table, values, conflict)
+}
+
+// BuildUpdate is like the package level BuildUpdate, using the
+// options of db.
+func (db *DB) BuildUpdate(table string, value interface{}, where string, args ...interface{}) (Statement, error) {
+	return buildUpdateStatement(db.config(), table, value, where, args...)
+}
+
+// BuildInsert is like the package level BuildInsert, using the
+// options of tx.
+func (tx *Tx) BuildInsert(table string, values interface{}) (Statement, error) {
+	return buildInsert(tx.config(), table, values)
+}
+
+// BuildUpsert is like the package level BuildUpsert, using the
+// options of tx.
+func (tx *Tx) BuildUpsert(table string, values interface{}, conflict ...string) (Statement, error) {
+	return buildUpsert(tx.config(), table, values, conflict)
+}
+
+// BuildUpdate is like the package level BuildUpdate, using the
+// options of tx.
+func (tx *Tx) BuildUpdate(table string, value interface{}, where string, args ...interface{}) (Statement, error) {
+	return buildUpdateStatement(tx.config(), table, value, where, args...)
+}
+
+func buildInsert(c config, table string, values interface{}) (Statement, error) {
+	i, err := insert(c, table, values)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{i.statement, i.args}, nil
+}
+
+func buildUpsert(c config, table string, values interface{}, conflict []string) (Statement, error) {
+	u, err := upsert(c, table, values, conflict)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{u.statement, u.args}, nil
+}
+
+func buildUpdateStatement(c config, table string, value interface{}, where string, args ...interface{}) (Statement, error) {
+	u, err := update(c, table, value, where, args...)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{u.statement, u.args}, nil
+}
@@ -0,0 +1,96 @@
+package sqlh
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	type row struct {
+		ID   int    `sql:"id"`
+		Name string `sql:"name"`
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		s, err := BuildInsert("X", []row{{1, "one"}, {2, "two"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := Statement{
+			SQL:  "insert into X(id, name) values($1, $2), ($3, $4)",
+			Args: []interface{}{1, "one", 2, "two"},
+		}
+		if !reflect.DeepEqual(expect, s) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, s)
+		}
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		s, err := BuildUpsert("X", row{1, "one"}, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := Statement{
+			SQL:  "insert into X(id, name) values($1, $2) on conflict(id) do update set name = excluded.name",
+			Args: []interface{}{1, "one"},
+		}
+		if !reflect.DeepEqual(expect, s) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, s)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		s, err := BuildUpdate("X", row{Name: "new"}, "id = $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := Statement{
+			SQL:  "UPDATE X SET name = $1 WHERE id = $2",
+			Args: []interface{}{"new", 1},
+		}
+		if !reflect.DeepEqual(expect, s) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, s)
+		}
+	})
+
+	t.Run("errors are returned", func(t *testing.T) {
+		if _, err := BuildUpdate("X", row{}, "id = $1", 1); err == nil {
+			t.Fatal("expected error for empty update")
+		}
+	})
+
+	t.Run("db options are used", func(t *testing.T) {
+		db := NewDB(nil, Options{Dialect: MySQL})
+		s, err := db.BuildUpdate("X", row{Name: "new"}, "id = ?", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.SQL != "UPDATE X SET name = ? WHERE id = ?" {
+			t.Fatalf("unexpected statement: %s", s.SQL)
+		}
+	})
+
+	t.Run("db and tx build with their options", func(t *testing.T) {
+		o := Options{Dialect: MySQL}
+		db := NewDB(nil, o)
+		tx := &Tx{nil, o}
+		for _, b := range []interface {
+			BuildInsert(string, interface{}) (Statement, error)
+			BuildUpsert(string, interface{}, ...string) (Statement, error)
+			BuildUpdate(string, interface{}, string, ...interface{}) (Statement, error)
+		}{db, tx} {
+			s, err := b.BuildInsert("X", row{1, "one"})
+			if err != nil || s.SQL != "insert into X(id, name) values(?, ?)" {
+				t.Fatalf("unexpected insert %#v: %v", s.SQL, err)
+			}
+			s, err = b.BuildUpsert("X", row{1, "one"}, "id")
+			if err != nil || s.SQL != "insert into X(id, name) values(?, ?) on duplicate key update name = values(name)" {
+				t.Fatalf("unexpected upsert %#v: %v", s.SQL, err)
+			}
+			s, err = b.BuildUpdate("X", row{Name: "new"}, "id = ?", 1)
+			if err != nil || s.SQL != "UPDATE X SET name = ? WHERE id = ?" {
+				t.Fatalf("unexpected update %#v: %v", s.SQL, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package sqlh
+
+// DefaultTag is the struct tag key used to map fields to columns in
+// the package level functions. Setting it to "db" allows structs
+// tagged for sqlx to be used as they are. It should be set before any
+// statements are run.
+var DefaultTag = "sql"
+
+// config holds the settings used to map struct fields to columns,
+// and to build statements.
+type config struct {
+	dialect Dialect
+	tag     string
+	mapper  *Mapper
+}
+
+// defaults returns the config used by the package level functions.
+func defaults() config {
+	return config{
+		dialect: DefaultDialect,
+		tag:     DefaultTag,
+		mapper:  DefaultMapper,
+	}
+}
+
+// configOf returns the config of db if it is a DB or Tx, or the
+// package level defaults otherwise.
+func configOf(db interface{}) config {
+	if o, ok := db.(interface{ config() config }); ok {
+		return o.config()
+	}
+	return defaults()
+}
+
+// tagKey returns the struct tag key used to map fields, which is "sql"
+// unless otherwise set.
+func (c config) tagKey() string {
+	if c.tag == "" {
+		return "sql"
+	}
+	return c.tag
+}
@@ -0,0 +1,64 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestTagKey(t *testing.T) {
+	type e struct {
+		ID int `db:"id,pk"`
+	}
+	type row struct {
+		e
+		Name    string `db:"name,omitempty"`
+		Email   string `db:","` // Named after the field
+		Ignored string `db:"-"`
+		Other   string `sql:"other"` // Not the tag in use
+	}
+
+	t.Run("insert statement", func(t *testing.T) {
+		c := config{dialect: Postgres, tag: "db"}
+		i, err := insert(c, "T", row{e{1}, "a", "b", "c", "d"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into T(id, name, email) values($1, $2, $3)`
+		if statement != i.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, i.statement)
+		}
+		if columns, _ := keyFields(c, reflect.TypeOf(row{})); !reflect.DeepEqual([]string{"id"}, columns) {
+			t.Fatalf("unexpected keys: %#v", columns)
+		}
+	})
+
+	t.Run("default tag key", func(t *testing.T) {
+		i, err := insert(config{dialect: Postgres}, "T", row{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into T(other) values($1)`
+		if statement != i.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, i.statement)
+		}
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		defer func(tag string) { DefaultTag = tag }(DefaultTag)
+		DefaultTag = "db"
+
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dest row
+		if err := Scan(&dest, db, `select 1 as id, 'a' as name, 'b' as email`); err != nil {
+			t.Fatal(err)
+		}
+		expect := row{e{1}, "a", "b", "", ""}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+}
@@ -0,0 +1,320 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+// Options are the settings used by a DB or Tx to map structs to
+// columns and build statements. Fields left as their zero value use
+// the package level defaults.
+type Options struct {
+	Dialect Dialect
+	Tag     string
+	Mapper  *Mapper
+	// Logger, if set, logs every statement with its arguments.
+	Logger *log.Logger
+	// Hooks are called around every statement, in order.
+	Hooks []Hook
+}
+
+func (o Options) config() config {
+	c := defaults()
+	if o.Dialect != (Dialect{}) {
+		c.dialect = o.Dialect
+	}
+	if o.Tag != "" {
+		c.tag = o.Tag
+	}
+	if o.Mapper != nil {
+		c.mapper = o.Mapper
+	}
+	return c
+}
+
+func (o Options) log(query string, args []interface{}, err error) {
+	switch {
+	case o.Logger == nil:
+	case err != nil:
+		o.Logger.Printf("%s: %s", query, err)
+	default:
+		o.Logger.Printf("%s %v", query, args)
+	}
+}
+
+// rowsAffected returns the rows affected by res, or -1 if unknown.
+func rowsAffected(res sql.Result) int64 {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// DB wraps an *sql.DB with its own Options, so that databases with
+// different settings can be used side by side.
+//
+//   db := NewDB(sqlDB, Options{Dialect: MySQL, Tag: "db"})
+//   err := db.Scan(&dest, `select a, b from C`)
+//
+// The package level functions also use the options of a DB or Tx
+// passed to them, e.g. Get(db, &dest, "C", 1).
+//
+// Statements run with Exec or Query may use named parameters, given
+// with Bind.
+type DB struct {
+	*sql.DB
+	Options
+}
+
+// NewDB returns a DB using db with the given options.
+func NewDB(db *sql.DB, o Options) *DB {
+	return &DB{DB: db, Options: o}
+}
+
+// Open opens a database as sql.Open does, returning a DB with the
+// given options.
+func Open(driverName, dataSourceName string, o Options) (*DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return NewDB(db, o), nil
+}
+
+// Begin starts a transaction with the same options as db.
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+// BeginTx starts a transaction with the same options as db.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, Options: db.Options}, nil
+}
+
+// Exec is like sql.DB.Exec, but logs the statement and calls the
+// hooks.
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext is like sql.DB.ExecContext, but logs the statement
+// and calls the hooks.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var res sql.Result
+	err := db.run(ctx, query, args, func(ctx context.Context, query string, args []interface{}) (int64, error) {
+		var err error
+		if res, err = db.DB.ExecContext(ctx, query, args...); err != nil {
+			return -1, err
+		}
+		return rowsAffected(res), nil
+	})
+	return res, err
+}
+
+// Query is like sql.DB.Query, but logs the query and calls the
+// hooks.
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext is like sql.DB.QueryContext, but logs the query and
+// calls the hooks.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := db.run(ctx, query, args, func(ctx context.Context, query string, args []interface{}) (int64, error) {
+		var err error
+		rows, err = db.DB.QueryContext(ctx, query, args...)
+		return -1, err
+	})
+	return rows, err
+}
+
+// QueryRow is like sql.DB.QueryRow, but logs the query and calls the
+// hooks.
+func (db *DB) QueryRow(query string, args ...interface{}) *Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext is like sql.DB.QueryRowContext, but logs the query
+// and calls the hooks.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	r := &Row{}
+	r.err = db.run(ctx, query, args, func(ctx context.Context, query string, args []interface{}) (int64, error) {
+		r.row = db.DB.QueryRowContext(ctx, query, args...)
+		return -1, r.row.Err()
+	})
+	return r
+}
+
+// Scan is like the package level Scan, using the options of db.
+func (db *DB) Scan(dest interface{}, query string, args ...interface{}) error {
+	return Scan(dest, db, query, args...)
+}
+
+// ScanContext is like the package level ScanContext, using the
+// options of db.
+func (db *DB) ScanContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return ScanContext(ctx, dest, db, query, args...)
+}
+
+// Insert is like the package level Insert, using the options of db.
+func (db *DB) Insert(table string, values interface{}) (sql.Result, error) {
+	return Insert(db, table, values)
+}
+
+// InsertContext is like the package level InsertContext, using the
+// options of db.
+func (db *DB) InsertContext(ctx context.Context, table string, values interface{}) (sql.Result, error) {
+	return InsertContext(ctx, db, table, values)
+}
+
+// Update is like the package level Update, using the options of db.
+func (db *DB) Update(table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
+	return Update(db, table, value, where, args...)
+}
+
+// UpdateContext is like the package level UpdateContext, using the
+// options of db.
+func (db *DB) UpdateContext(ctx context.Context, table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
+	return UpdateContext(ctx, db, table, value, where, args...)
+}
+
+// InsertBatchTx is like the package level InsertBatchTx, using the
+// options of db in a Tx begun from it.
+func (db *DB) InsertBatchTx(ctx context.Context, table string, values interface{}, maxParams int) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	n, err := InsertBatchContext(ctx, tx, table, values, maxParams)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// Row is the result of QueryRow on a DB or Tx. It is like an *sql.Row,
+// but also reports errors binding named parameters, in which case the
+// query is not run.
+type Row struct {
+	row *sql.Row
+	err error // Set if the query was not run
+}
+
+// Scan is like sql.Row.Scan.
+func (r *Row) Scan(dest ...interface{}) error {
+	if r.row == nil {
+		return r.err
+	}
+	return r.row.Scan(dest...)
+}
+
+// Err is like sql.Row.Err.
+func (r *Row) Err() error {
+	if r.row == nil {
+		return r.err
+	}
+	return r.row.Err()
+}
+
+// Tx wraps an *sql.Tx with its own Options. It is created by
+// DB.Begin, and behaves as a DB does.
+type Tx struct {
+	*sql.Tx
+	Options
+}
+
+// Exec is like sql.Tx.Exec, but logs the statement and calls the
+// hooks.
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext is like sql.Tx.ExecContext, but logs the statement
+// and calls the hooks.
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var res sql.Result
+	err := tx.run(ctx, query, args, func(ctx context.Context, query string, args []interface{}) (int64, error) {
+		var err error
+		if res, err = tx.Tx.ExecContext(ctx, query, args...); err != nil {
+			return -1, err
+		}
+		return rowsAffected(res), nil
+	})
+	return res, err
+}
+
+// Query is like sql.Tx.Query, but logs the query and calls the
+// hooks.
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext is like sql.Tx.QueryContext, but logs the query and
+// calls the hooks.
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := tx.run(ctx, query, args, func(ctx context.Context, query string, args []interface{}) (int64, error) {
+		var err error
+		rows, err = tx.Tx.QueryContext(ctx, query, args...)
+		return -1, err
+	})
+	return rows, err
+}
+
+// QueryRow is like sql.Tx.QueryRow, but logs the query and calls the
+// hooks.
+func (tx *Tx) QueryRow(query string, args ...interface{}) *Row {
+	return tx.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext is like sql.Tx.QueryRowContext, but logs the query
+// and calls the hooks.
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	r := &Row{}
+	r.err = tx.run(ctx, query, args, func(ctx context.Context, query string, args []interface{}) (int64, error) {
+		r.row = tx.Tx.QueryRowContext(ctx, query, args...)
+		return -1, r.row.Err()
+	})
+	return r
+}
+
+// Scan is like the package level Scan, using the options of tx.
+func (tx *Tx) Scan(dest interface{}, query string, args ...interface{}) error {
+	return Scan(dest, tx, query, args...)
+}
+
+// ScanContext is like the package level ScanContext, using the
+// options of tx.
+func (tx *Tx) ScanContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return ScanContext(ctx, dest, tx, query, args...)
+}
+
+// Insert is like the package level Insert, using the options of tx.
+func (tx *Tx) Insert(table string, values interface{}) (sql.Result, error) {
+	return Insert(tx, table, values)
+}
+
+// InsertContext is like the package level InsertContext, using the
+// options of tx.
+func (tx *Tx) InsertContext(ctx context.Context, table string, values interface{}) (sql.Result, error) {
+	return InsertContext(ctx, tx, table, values)
+}
+
+// Update is like the package level Update, using the options of tx.
+func (tx *Tx) Update(table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
+	return Update(tx, table, value, where, args...)
+}
+
+// UpdateContext is like the package level UpdateContext, using the
+// options of tx.
+func (tx *Tx) UpdateContext(ctx context.Context, table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
+	return UpdateContext(ctx, tx, table, value, where, args...)
+}
@@ -0,0 +1,120 @@
+package sqlh
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDB(t *testing.T) {
+	type row struct {
+		ID   int    `db:"id" sql:"id/insert"`
+		Name string `db:"name" sql:"name"`
+	}
+
+	var buf bytes.Buffer
+	db, err := Open("sqlite3", ":memory:", Options{
+		Dialect: SQLite,
+		Tag:     "db",
+		Logger:  log.New(&buf, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`create table T(id integer primary key, name text)`); err != nil {
+		t.Fatal(err)
+	}
+	// Same database, with package level defaults
+	plain := NewDB(db.DB, Options{})
+
+	t.Run("insert with db options", func(t *testing.T) {
+		buf.Reset()
+		if _, err := db.Insert("T", []row{{1, "one"}, {2, "two"}}); err != nil {
+			t.Fatal(err)
+		}
+		logged := "insert into T(id, name) values(?1, ?2), (?3, ?4) [1 one 2 two]\n"
+		if logged != buf.String() {
+			t.Fatalf("expected log %#v, got: %#v", logged, buf.String())
+		}
+	})
+
+	t.Run("insert with default options", func(t *testing.T) {
+		if _, err := plain.Insert("T", row{Name: "three"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("update and scan", func(t *testing.T) {
+		if _, err := db.Update("T", row{Name: "updated"}, "id = ?1", 1); err != nil {
+			t.Fatal(err)
+		}
+		var dest []row
+		if err := db.Scan(&dest, `select * from T order by id`); err != nil {
+			t.Fatal(err)
+		}
+		expect := []row{{1, "updated"}, {2, "two"}, {3, "three"}}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+
+	t.Run("package functions use db options", func(t *testing.T) {
+		buf.Reset()
+		var dest []row
+		if err := Scan(&dest, db, `select * from T where id = ?1`, 2); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "where id = ?1") {
+			t.Fatalf("expected query to be logged, got: %#v", buf.String())
+		}
+	})
+
+	t.Run("transaction keeps options", func(t *testing.T) {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Update("T", row{Name: "tx"}, "id = ?1", 2); err != nil {
+			tx.Rollback()
+			t.Fatal(err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		var name string
+		if err := db.Scan(&name, `select name from T where id = 2`); err != nil {
+			t.Fatal(err)
+		}
+		if name != "two" {
+			t.Fatalf("expected update to be rolled back, got: %s", name)
+		}
+	})
+	t.Run("batch insert in a transaction", func(t *testing.T) {
+		buf.Reset()
+		rows := []row{{4, "four"}, {5, "five"}, {6, "six"}}
+		n, err := db.InsertBatchTx(context.Background(), "T", rows, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 3 {
+			t.Fatalf("expected 3 rows, got: %d", n)
+		}
+		if !strings.Contains(buf.String(), "values(?1, ?2), (?3, ?4) [4 four 5 five]") {
+			t.Fatalf("expected db options to be used, got: %#v", buf.String())
+		}
+		if _, err := db.InsertBatchTx(context.Background(), "T", []row{{7, "seven"}, {4, "dup"}}, 0); err == nil {
+			t.Fatal("expected duplicate key error")
+		}
+		var count int
+		if err := db.Scan(&count, `select count(*) from T where id = 7`); err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Fatal("expected failed batch to be rolled back")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Delete runs an SQL DELETE query. It takes a database, target table,
+// and where clause with arguments.
+//
+//   res, err := Delete(db, "X", "id = $1", 1)
+//   // = db.Exec(`DELETE FROM X WHERE id = $1`, 1)
+func Delete(db Executor, table string, where string, args ...interface{}) (sql.Result, error) {
+	where, args, err := prepare(configOf(db), where, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	d := deleteWhere(table, where, args...)
+	return db.Exec(d.statement, d.args...)
+}
+
+// DeleteContext is like Delete, but executes the statement with a
+// context.
+func DeleteContext(ctx context.Context, db ExecutorContext, table string, where string, args ...interface{}) (sql.Result, error) {
+	where, args, err := prepare(configOf(db), where, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	d := deleteWhere(table, where, args...)
+	return db.ExecContext(ctx, d.statement, d.args...)
+}
+
+// DeleteByKey runs an SQL DELETE query for the row identified by the
+// key fields of value. Key fields are those tagged with the pk
+// option; when there are several, all must match.
+//
+//   type row struct{
+//       Id int `sql:"id,pk"`
+//       Name string `sql:"name"`
+//   }
+//   res, err := DeleteByKey(db, "X", row{Id: 1})
+//   // = db.Exec(`DELETE FROM X WHERE id = $1`, 1)
+func DeleteByKey(db Executor, table string, value interface{}) (sql.Result, error) {
+	d, err := deleteByKey(configOf(db), table, value)
+	if err != nil {
+		return nil, err
+	}
+	return db.Exec(d.statement, d.args...)
+}
+
+// DeleteByKeyContext is like DeleteByKey, but executes the statement
+// with a context.
+func DeleteByKeyContext(ctx context.Context, db ExecutorContext, table string, value interface{}) (sql.Result, error) {
+	d, err := deleteByKey(configOf(db), table, value)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, d.statement, d.args...)
+}
+
+type preDelete struct {
+	statement string
+	args      []interface{}
+}
+
+func deleteWhere(table string, where string, args ...interface{}) *preDelete {
+	return &preDelete{
+		statement: fmt.Sprintf("DELETE FROM %s WHERE %s", table, where),
+		args:      args,
+	}
+}
+
+func deleteByKey(c config, table string, value interface{}) (*preDelete, error) {
+	where, args, err := keyWhere(c, value)
+	if err != nil {
+		return nil, err
+	}
+	return deleteWhere(table, where, args...), nil
+}
@@ -0,0 +1,80 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	type e struct {
+		A int `sql:"a,pk"`
+	}
+	type row struct {
+		e
+		B int    `sql:"b,pk/update"`
+		C string `sql:"c"`
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`create table T(a int, b int, c text)`); err != nil {
+		t.Fatal(err)
+	}
+	rows := []row{{e{1}, 1, "x"}, {e{1}, 2, "x"}, {e{2}, 1, "x"}, {e{3}, 1, "x"}}
+	if _, err := Insert(db, "T", rows); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("key statement", func(t *testing.T) {
+		d, err := deleteByKey(config{dialect: SQLServer}, "T", row{e{1}, 2, "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preDelete{
+			statement: `DELETE FROM T WHERE a = @p1 AND b = @p2`,
+			args:      []interface{}{1, 2},
+		}
+		if !reflect.DeepEqual(exp, *d) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *d)
+		}
+	})
+
+	t.Run("no key fields fails", func(t *testing.T) {
+		type row struct {
+			A int `sql:"a"`
+		}
+		if _, err := DeleteByKey(db, "T", row{1}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("delete by key", func(t *testing.T) {
+		res, err := DeleteByKey(db, "T", row{e{1}, 2, ""})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := res.RowsAffected(); n != 1 {
+			t.Fatalf("expected 1 row affected, got: %d", n)
+		}
+	})
+
+	t.Run("delete with where", func(t *testing.T) {
+		res, err := Delete(db, "T", "a > $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := res.RowsAffected(); n != 2 {
+			t.Fatalf("expected 2 rows affected, got: %d", n)
+		}
+		var dest []row
+		if err := Scan(&dest, db, `select * from T`); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([]row{{e{1}, 1, "x"}}, dest) {
+			t.Fatalf("unexpected rows: %#v", dest)
+		}
+	})
+}
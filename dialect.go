@@ -0,0 +1,63 @@
+package sqlh
+
+import (
+	"strconv"
+)
+
+// Dialect describes how a database expects bind parameters to be
+// written in a statement.
+//
+// Where clauses passed to Update must use the placeholders of the
+// dialect in use, they are reindexed accordingly.
+type Dialect struct {
+	// Prefix is written before each bind parameter, e.g. "$" or "?".
+	Prefix string
+	// Numbered is set if the prefix is followed by the 1-based
+	// index of the argument, as in "$1, $2".
+	Numbered bool
+	// DuplicateKey is set if upserts are written with ON DUPLICATE
+	// KEY UPDATE, rather than ON CONFLICT.
+	DuplicateKey bool
+}
+
+var (
+	// Postgres writes bind parameters as $1, $2, ...
+	Postgres = Dialect{Prefix: "$", Numbered: true}
+	// MySQL writes bind parameters as ?, ?, ...
+	MySQL = Dialect{Prefix: "?", DuplicateKey: true}
+	// SQLite writes bind parameters as ?1, ?2, ... Unnumbered
+	// placeholders can still be used in where clauses, as SQLite
+	// numbers them after the largest index seen so far.
+	SQLite = Dialect{Prefix: "?", Numbered: true}
+	// SQLServer writes bind parameters as @p1, @p2, ...
+	SQLServer = Dialect{Prefix: "@p", Numbered: true}
+	// Oracle writes bind parameters as :1, :2, ...
+	Oracle = Dialect{Prefix: ":", Numbered: true}
+)
+
+// DefaultDialect is the dialect used to build statements in Insert,
+// Upsert and Update. It should be set before any statements are run.
+var DefaultDialect = Postgres
+
+// placeholder returns the bind parameter for the n'th argument.
+func (d Dialect) placeholder(n int) string {
+	if !d.Numbered {
+		return d.Prefix
+	}
+	return d.Prefix + strconv.Itoa(n)
+}
+
+// placeholders returns a comma separated list of n bind parameters,
+// starting after the base'th argument.
+func (d Dialect) placeholders(base, n int) string {
+	if n < 0 {
+		panic("n < 0")
+	}
+	v := ""
+	sep := ""
+	for i := 1; i <= n; i++ {
+		v += sep + d.placeholder(base+i)
+		sep = ", "
+	}
+	return v
+}
@@ -0,0 +1,104 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// UpdateDiff runs an SQL UPDATE query setting the columns whose
+// fields differ between old and new, two values of the same struct
+// type. Unlike Update, changes to zero values are written, and
+// unchanged fields are not. It returns the changed columns.
+//
+//   old := row{Id: 1, Name: "name", Count: 2}
+//   new := row{Id: 1, Name: "name"}
+//   res, changed, err := UpdateDiff(db, "X", old, new, "id = $1", 1)
+//   // = db.Exec(`UPDATE X SET count = $1 WHERE id = $2`, 0, 1)
+//
+// Fields ignored in the update context, and unset fields of new, are
+// never set. If no columns changed, no statement is run and the
+// result is nil. Old and new may also be pointers to structs.
+func UpdateDiff(db Executor, table string, old, new interface{}, where string, args ...interface{}) (sql.Result, []string, error) {
+	u, changed, err := updateDiff(configOf(db), table, old, new, where, args...)
+	if err != nil || u == nil {
+		return nil, changed, err
+	}
+	res, err := db.Exec(u.statement, u.args...)
+	return res, changed, err
+}
+
+// UpdateDiffContext is like UpdateDiff, but executes the statement
+// with a context.
+func UpdateDiffContext(ctx context.Context, db ExecutorContext, table string, old, new interface{}, where string, args ...interface{}) (sql.Result, []string, error) {
+	u, changed, err := updateDiff(configOf(db), table, old, new, where, args...)
+	if err != nil || u == nil {
+		return nil, changed, err
+	}
+	res, err := db.ExecContext(ctx, u.statement, u.args...)
+	return res, changed, err
+}
+
+// updateDiff returns the update setting the changed columns between
+// old and new, or nil if there are none, along with the changed
+// columns.
+func updateDiff(c config, table string, old, new interface{}, where string, args ...interface{}) (*preUpdate, []string, error) {
+	o, err := diffValue(old)
+	if err != nil {
+		return nil, nil, err
+	}
+	n, err := diffValue(new)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.Type() != n.Type() {
+		return nil, nil, fmt.Errorf("type mismatch: %v and %v", o.Type(), n.Type())
+	}
+	p := planFor(c, n.Type(), "update")
+	var (
+		changed []string
+		set     []string
+		vals    []interface{}
+	)
+	for i, name := range p.columns {
+		value := n.FieldByIndex(p.index[i])
+		if unset(value) || same(o.FieldByIndex(p.index[i]), value) {
+			continue
+		}
+		changed = append(changed, name)
+		set = append(set, name+" = "+c.dialect.placeholder(len(vals)+1))
+		vals = append(vals, value.Interface())
+	}
+	if len(set) < 1 {
+		return nil, nil, nil
+	}
+	u, err := buildUpdate(c, table, set, vals, where, args...)
+	return u, changed, err
+}
+
+// diffValue returns the struct v, or the struct v points to.
+func diffValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case !rv.IsValid():
+		return rv, fmt.Errorf("update was nil")
+	case rv.Kind() == reflect.Ptr && rv.IsNil():
+		return rv, fmt.Errorf("update was a nil pointer: %v", rv.Type())
+	case rv.Kind() == reflect.Ptr:
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, fmt.Errorf("update was not a struct: %v", rv.Type())
+	}
+	return rv, nil
+}
+
+// same reports whether fields a and b of the same type hold the same
+// value. Pointers are followed, and times are compared by instant.
+func same(a, b reflect.Value) bool {
+	if hashable(a.Type()) {
+		return keyValue(a) == keyValue(b)
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
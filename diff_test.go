@@ -0,0 +1,106 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUpdateDiff(t *testing.T) {
+	type e struct {
+		Count *int `sql:"count"`
+	}
+	type T struct {
+		e
+		ID      int       `sql:"id/update"`
+		Name    string    `sql:"name"`
+		Tags    []string  `sql:"tags"`
+		Created time.Time `sql:"created"`
+	}
+	c := config{dialect: Postgres}
+	one, two := 1, 1
+	now := time.Now()
+
+	t.Run("changed columns are set", func(t *testing.T) {
+		old := T{e{&one}, 1, "name", []string{"a"}, now}
+		new := T{e{nil}, 2, "", []string{"a"}, now.UTC()}
+		u, changed, err := updateDiff(c, "T", old, new, "id = $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preUpdate{
+			statement: "UPDATE T SET count = $1, name = $2 WHERE id = $3",
+			args:      []interface{}{(*int)(nil), "", 1},
+		}
+		if !reflect.DeepEqual(exp, *u) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
+		}
+		if !reflect.DeepEqual([]string{"count", "name"}, changed) {
+			t.Fatalf("unexpected changed columns: %#v", changed)
+		}
+	})
+
+	t.Run("equal values give no update", func(t *testing.T) {
+		old := T{e{&one}, 1, "name", []string{"a"}, now}
+		new := T{e{&two}, 1, "name", []string{"a"}, now}
+		u, changed, err := updateDiff(c, "T", old, new, "id = $1", 1)
+		if err != nil || u != nil || changed != nil {
+			t.Fatalf("expected no update, got: %#v, %#v, %v", u, changed, err)
+		}
+	})
+
+	t.Run("nil values fail", func(t *testing.T) {
+		var nilT *T
+		for _, v := range [][2]interface{}{{nil, T{}}, {T{}, nil}, {nilT, T{}}, {&T{}, nilT}} {
+			if _, _, err := updateDiff(c, "T", v[0], v[1], "id = $1", 1); err == nil {
+				t.Fatalf("expected error for %#v", v)
+			}
+		}
+	})
+
+	t.Run("pointers to structs", func(t *testing.T) {
+		_, changed, err := updateDiff(c, "T", &T{Name: "a"}, &T{Name: "b"}, "id = $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([]string{"name"}, changed) {
+			t.Fatalf("unexpected changed columns: %#v", changed)
+		}
+	})
+
+	t.Run("type mismatch fails", func(t *testing.T) {
+		if _, _, err := updateDiff(c, "T", e{}, T{}, "id = $1", 1); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		type R struct {
+			ID   int    `sql:"id"`
+			Name string `sql:"name"`
+		}
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.Exec(`create table R(id int, name text); insert into R values(1, 'name')`); err != nil {
+			t.Fatal(err)
+		}
+		_, changed, err := UpdateDiff(db, "R", R{1, "name"}, R{1, ""}, "id = ?", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([]string{"name"}, changed) {
+			t.Fatalf("unexpected changed columns: %#v", changed)
+		}
+		var name string
+		if err := Scan(&name, db, `select name from R`); err != nil {
+			t.Fatal(err)
+		}
+		if name != "" {
+			t.Fatalf("expected name to be cleared, got: %s", name)
+		}
+	})
+}
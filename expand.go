@@ -0,0 +1,122 @@
+package sqlh
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// prepare returns query and args ready to run: named parameters are
+// bound if args is a single Params, slice arguments are expanded into
+// lists, and placeholders are numbered after the base'th argument.
+func prepare(c config, query string, args []interface{}, base int) (string, []interface{}, error) {
+	if p, ok := params(args); ok {
+		var err error
+		if query, args, err = p.bind(c, query, 0); err != nil {
+			return "", nil, err
+		}
+	}
+	if !hasList(args) {
+		if base > 0 {
+			query = reindex(query, c.dialect, base)
+		}
+		return query, args, nil
+	}
+	return expand(query, c.dialect, args, base)
+}
+
+// expand replaces the placeholder of each slice argument in query,
+// written in dialect d, with a list of placeholders for its elements,
+// so that slices can be given for IN clauses:
+//
+//   expand("a in ($1) and b = $2", Postgres, []interface{}{[]int{1, 2}, 3}, 0)
+//   // = "a in ($1, $2) and b = $3", []interface{}{1, 2, 3}
+//
+// Placeholders are numbered after the base'th argument. Empty slices
+// are an error, as no list can be written for them, as is a slice
+// without a placeholder in the dialect.
+func expand(query string, d Dialect, args []interface{}, base int) (string, []interface{}, error) {
+	// Position of each argument's first element in the new arguments
+	var expanded []interface{}
+	start := make([]int, len(args))
+	for i, arg := range args {
+		start[i] = len(expanded)
+		v, ok := list(arg)
+		if !ok {
+			expanded = append(expanded, arg)
+			continue
+		}
+		if v.Len() == 0 {
+			return "", nil, fmt.Errorf("empty slice given for argument %d", i+1)
+		}
+		for j := 0; j < v.Len(); j++ {
+			expanded = append(expanded, v.Index(j).Interface())
+		}
+	}
+
+	// placeholders returns the list for the n'th argument
+	var err error
+	used := make([]bool, len(args))
+	placeholders := func(n int) string {
+		if n < 1 || n > len(args) {
+			if err == nil {
+				err = fmt.Errorf("no argument for placeholder %s", d.placeholder(n))
+			}
+			return ""
+		}
+		used[n-1] = true
+		if v, ok := list(args[n-1]); ok {
+			return d.placeholders(base+start[n-1], v.Len())
+		}
+		return d.placeholder(base + start[n-1] + 1)
+	}
+
+	if d.Numbered {
+		query = rewrite(query, d, placeholders)
+	} else {
+		// Unnumbered placeholders take the arguments in order
+		n := 0
+		prefix := []rune(d.Prefix)
+		query = substitute(query, func(in []rune, i int) (int, string) {
+			if !hasPrefix(in[i:], prefix) {
+				return 0, ""
+			}
+			n++
+			return len(prefix), placeholders(n)
+		})
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// A slice whose placeholder is missing, or not written in the
+	// dialect, would leave the arguments out of step
+	for i, arg := range args {
+		if _, ok := list(arg); ok && !used[i] {
+			return "", nil, fmt.Errorf("no placeholder for slice argument %d", i+1)
+		}
+	}
+	return query, expanded, nil
+}
+
+// hasList reports whether any of args is a slice to be expanded.
+func hasList(args []interface{}) bool {
+	for _, arg := range args {
+		if _, ok := list(arg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns arg as a slice to be expanded, unless it is a []byte
+// or a driver.Valuer, which drivers handle themselves.
+func list(arg interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 || v.Type().Implements(valuerType) {
+		return v, false
+	}
+	return v, true
+}
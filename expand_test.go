@@ -0,0 +1,120 @@
+package sqlh
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+type valuerSlice []int
+
+func (valuerSlice) Value() (driver.Value, error) { return "{}", nil }
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     Dialect
+		base  int
+		query string
+		args  []interface{}
+		exp   string
+		exArg []interface{}
+	}{
+		{"list and following placeholders", Postgres, 0,
+			"a in ($1) and b = $2", []interface{}{[]int{1, 2}, 3},
+			"a in ($1, $2) and b = $3", []interface{}{1, 2, 3}},
+		{"repeated list", SQLite, 0,
+			"a = ?2 and (b in (?1) or c in (?1))", []interface{}{[]string{"x", "y"}, 3},
+			"a = ?3 and (b in (?1, ?2) or c in (?1, ?2))", []interface{}{"x", "y", 3}},
+		{"base", SQLServer, 2,
+			"a = @p1 and b in (@p2)", []interface{}{1, []int{2, 3}},
+			"a = @p3 and b in (@p4, @p5)", []interface{}{1, 2, 3}},
+		{"unnumbered", MySQL, 2,
+			"a in (?) and b = ? and c = '?'", []interface{}{[]int{1, 2}, 3},
+			"a in (?, ?) and b = ? and c = '?'", []interface{}{1, 2, 3}},
+		{"bytes and valuers are not lists", Postgres, 0,
+			"a = $1 and b = $2 and c in ($3)", []interface{}{[]byte("x"), valuerSlice{1}, []int{1}},
+			"a = $1 and b = $2 and c in ($3)", []interface{}{[]byte("x"), valuerSlice{1}, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := prepare(config{dialect: tt.d}, tt.query, tt.args, tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.exp != query {
+				t.Fatalf("expected: %#v\ngot: %#v", tt.exp, query)
+			}
+			if !reflect.DeepEqual(tt.exArg, args) {
+				t.Fatalf("expected: %#v\ngot: %#v", tt.exArg, args)
+			}
+		})
+	}
+
+	t.Run("empty slice fails", func(t *testing.T) {
+		_, _, err := prepare(config{dialect: Postgres}, "a in ($1)", []interface{}{[]int{}}, 0)
+		if err == nil || err.Error() != "empty slice given for argument 1" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("missing argument fails", func(t *testing.T) {
+		_, _, err := prepare(config{dialect: Postgres}, "a in ($1) and b = $3", []interface{}{[]int{1}}, 0)
+		if err == nil || err.Error() != "no argument for placeholder $3" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unreferenced slice fails", func(t *testing.T) {
+		type T struct {
+			B string `sql:"b"`
+		}
+		_, err := update(config{dialect: SQLite}, "T", T{"z"}, "a in (?)", []int{1, 2})
+		if err == nil || err.Error() != "no placeholder for slice argument 1" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, _, err = prepare(config{dialect: Postgres}, "a = $2", []interface{}{[]int{1}, 2}, 0)
+		if err == nil || err.Error() != "no placeholder for slice argument 1" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("named list", func(t *testing.T) {
+		query, args, err := prepare(config{dialect: Postgres}, "a in (:ids)", []interface{}{Bind(map[string]interface{}{"ids": []int{1, 2}})}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query != "a in ($2, $3)" || !reflect.DeepEqual([]interface{}{1, 2}, args) {
+			t.Fatalf("unexpected query %#v with args %#v", query, args)
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec(`create table T(id int, name text); insert into T values(1, 'a'), (2, 'b'), (3, 'c')`); err != nil {
+			t.Fatal(err)
+		}
+		type row struct {
+			Name string `sql:"name"`
+		}
+		if _, err := Update(db, "T", row{"x"}, "id in ($1)", []int{1, 2}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Delete(db, "T", "id in ($1)", []int{3}); err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		if err := Scan(&names, db, `select name from T where name in ($1) order by id`, []string{"x", "y"}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([]string{"x", "x"}, names) {
+			t.Fatalf("unexpected names: %#v", names)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ScanAll runs a query and returns every row as a T, using the same
+// rules as Scan with a *[]T destination.
+//
+//   rows, err := ScanAll[row](db, `select a, b from C`)
+func ScanAll[T any](db Querist, query string, args ...interface{}) ([]T, error) {
+	var dest []T
+	err := Scan(&dest, db, query, args...)
+	return dest, err
+}
+
+// ScanAllContext is like ScanAll, but runs the query with a context.
+func ScanAllContext[T any](ctx context.Context, db QueristContext, query string, args ...interface{}) ([]T, error) {
+	var dest []T
+	err := ScanContext(ctx, &dest, db, query, args...)
+	return dest, err
+}
+
+// ScanOne runs a query and returns the first row as a T, using the
+// same rules as Scan with a *T destination. If there are no rows,
+// sql.ErrNoRows is returned.
+//
+//   n, err := ScanOne[int](db, `select count(*) from C`)
+func ScanOne[T any](db Querist, query string, args ...interface{}) (T, error) {
+	var dest T
+	err := Scan(&dest, db, query, args...)
+	return dest, err
+}
+
+// ScanOneContext is like ScanOne, but runs the query with a context.
+func ScanOneContext[T any](ctx context.Context, db QueristContext, query string, args ...interface{}) (T, error) {
+	var dest T
+	err := ScanContext(ctx, &dest, db, query, args...)
+	return dest, err
+}
+
+// InsertT is like Insert, but takes the values to insert as
+// arguments of a single type.
+//
+//   res, err := InsertT(db, "X", row{1, "test"}, row{2, "test"})
+func InsertT[T any](db Executor, table string, values ...T) (sql.Result, error) {
+	return Insert(db, table, values)
+}
+
+// InsertTContext is like InsertT, but executes the statement with a
+// context.
+func InsertTContext[T any](ctx context.Context, db ExecutorContext, table string, values ...T) (sql.Result, error) {
+	return InsertContext(ctx, db, table, values)
+}
+
+// UpdateT is like Update, but only accepts a value of the given type.
+//
+//   res, err := UpdateT(db, "X", row{Name: "updated"}, "id = $1", 1)
+func UpdateT[T any](db Executor, table string, value T, where string, args ...interface{}) (sql.Result, error) {
+	return Update(db, table, value, where, args...)
+}
+
+// UpdateTContext is like UpdateT, but executes the statement with a
+// context.
+func UpdateTContext[T any](ctx context.Context, db ExecutorContext, table string, value T, where string, args ...interface{}) (sql.Result, error) {
+	return UpdateContext(ctx, db, table, value, where, args...)
+}
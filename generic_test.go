@@ -0,0 +1,64 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestGeneric(t *testing.T) {
+	type row struct {
+		A int    `sql:"a"`
+		B string `sql:"b"`
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`create table T(a int, b text)`); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := []row{{1, "one"}, {2, "two"}}
+	if _, err := InsertT(db, "T", rows...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpdateT(db, "T", row{B: "updated"}, "a = $1", 2); err != nil {
+		t.Fatal(err)
+	}
+	rows[1].B = "updated"
+
+	t.Run("scan all", func(t *testing.T) {
+		dest, err := ScanAll[row](db, `select * from T`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rows, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", rows, dest)
+		}
+	})
+
+	t.Run("scan one", func(t *testing.T) {
+		dest, err := ScanOne[row](db, `select * from T where a = $1`, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rows[1], dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", rows[1], dest)
+		}
+		n, err := ScanOne[int](db, `select count(*) from T`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Fatalf("expected 2, got: %d", n)
+		}
+	})
+
+	t.Run("scan one without rows", func(t *testing.T) {
+		if _, err := ScanOne[row](db, `select * from T where a = 3`); err != sql.ErrNoRows {
+			t.Fatalf("expected %v, got: %v", sql.ErrNoRows, err)
+		}
+	})
+}
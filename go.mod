@@ -1,5 +1,5 @@
 module github.com/tgrid-archive/sqlh
 
-go 1.13
+go 1.22
 
 require github.com/mattn/go-sqlite3 v2.0.3+incompatible
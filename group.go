@@ -0,0 +1,343 @@
+package sqlh
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// isRow reports whether t is a struct whose fields are mapped to
+// columns, rather than a struct which is scanned as a single value,
+// such as time.Time or an sql.Scanner.
+func isRow(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PtrTo(t).Implements(scannerType)
+}
+
+// nested returns the row type of a slice of structs, or of struct
+// pointers, and whether it holds pointers. If t is not such a slice,
+// nil is returned.
+func nested(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Slice {
+		return nil, false
+	}
+	t = t.Elem()
+	if t.Kind() == reflect.Ptr && isRow(t.Elem()) {
+		return t.Elem(), true
+	}
+	if isRow(t) {
+		return t, false
+	}
+	return nil, false
+}
+
+// group describes how columns are scanned into a struct type, and
+// how rows are grouped together. Slices of structs within the type
+// are described by child groups.
+type group struct {
+	t          reflect.Type
+	index      []int   // Field index of the slice in the parent
+	elemPtr    bool    // Whether the slice holds pointers
+	columns    []int   // Position in the row of each column
+	fields     [][]int // Field index of each column
+	keys       []int   // Columns of non-slice fields
+	aggregates []int   // Columns of slice fields
+	children   []*group
+
+	// When the key fields can be hashed, seen maps the keys of
+	// each element added to the slice to its position, so rows
+	// are grouped in linear time.
+	hashable bool
+	seen     map[interface{}]int
+}
+
+// newGroup maps columns onto the fields of struct type t. A column
+// may also be mapped into a slice of structs field, by prefixing it
+// with the field's name and "_". E.g., the column order_id maps into
+// `sql:"id"` of the struct type of `sql:"order"`.
+func newGroup(c config, t reflect.Type, columns []string) (*group, error) {
+	g := newChild(t, nil, false)
+	for i, col := range columns {
+		if !g.add(c, i, col) {
+			return nil, fmt.Errorf("no field for column %s", col)
+		}
+	}
+	return g, nil
+}
+
+// add maps column i, named col, into g or one of its children.
+func (g *group) add(c config, i int, col string) bool {
+	p := planFor(c, g.t, "select")
+	if j, ok := p.lookup[col]; ok {
+		ft := g.t.FieldByIndex(p.index[j]).Type
+		if rt, _ := nested(ft); rt == nil {
+			g.columns = append(g.columns, i)
+			g.fields = append(g.fields, p.index[j])
+			if ft.Kind() == reflect.Slice {
+				g.aggregates = append(g.aggregates, len(g.columns)-1)
+			} else {
+				g.keys = append(g.keys, len(g.columns)-1)
+				g.hashable = g.hashable && hashable(ft)
+			}
+			return true
+		}
+	}
+	for j, name := range p.columns {
+		rt, ptr := nested(g.t.FieldByIndex(p.index[j]).Type)
+		if rt == nil || !strings.HasPrefix(col, name+"_") {
+			continue
+		}
+		if g.child(p.index[j], rt, ptr).add(c, i, strings.TrimPrefix(col, name+"_")) {
+			return true
+		}
+	}
+	return false
+}
+
+// child returns the child group for the slice at field index, adding
+// it if needed.
+func (g *group) child(index []int, t reflect.Type, ptr bool) *group {
+	for _, c := range g.children {
+		if reflect.DeepEqual(c.index, index) {
+			return c
+		}
+	}
+	c := newChild(t, index, ptr)
+	g.children = append(g.children, c)
+	return c
+}
+
+func newChild(t reflect.Type, index []int, ptr bool) *group {
+	return &group{
+		t:        t,
+		index:    index,
+		elemPtr:  ptr,
+		hashable: true,
+		seen:     make(map[interface{}]int),
+	}
+}
+
+// grouped reports whether rows need to be grouped, which is only when
+// there is a slice to aggregate into.
+func (g *group) grouped() bool {
+	return len(g.aggregates) > 0 || len(g.children) > 0
+}
+
+// row is a single row scanned into a group.
+type row struct {
+	value    reflect.Value   // Struct the row is scanned into
+	temps    []reflect.Value // Temporary receiver for each column
+	children []*row
+}
+
+// newRow returns a row scanning into value, filling in receivers.
+// Only the root group scans directly into its fields, other groups
+// use temporary receivers to allow for NULL from an outer join.
+func (g *group) newRow(value reflect.Value, receivers []interface{}, root bool) *row {
+	r := &row{value: value, temps: make([]reflect.Value, len(g.columns))}
+	for k, i := range g.columns {
+		ft := value.Type().FieldByIndex(g.fields[k]).Type
+		if root && ft.Kind() != reflect.Slice {
+			receivers[i] = value.FieldByIndex(g.fields[k]).Addr().Interface()
+			continue
+		}
+		if ft.Kind() == reflect.Slice {
+			ft = ft.Elem() // Scan an element for aggregation
+		}
+		r.temps[k] = reflect.New(reflect.PtrTo(ft))
+		receivers[i] = r.temps[k].Interface()
+	}
+	for _, c := range g.children {
+		r.children = append(r.children, c.newRow(reflect.New(c.t).Elem(), receivers, false))
+	}
+	return r
+}
+
+// null reports whether every column of a child row was NULL.
+func (g *group) null(r *row) bool {
+	for _, temp := range r.temps {
+		if !temp.Elem().IsNil() {
+			return false
+		}
+	}
+	return true
+}
+
+// fill sets the non-slice fields of a child row from its temporary
+// receivers.
+func (g *group) fill(r *row) {
+	for _, k := range g.keys {
+		if v := r.temps[k].Elem(); !v.IsNil() {
+			r.value.FieldByIndex(g.fields[k]).Set(v.Elem())
+		}
+	}
+}
+
+// merge adds r into the slice dst. If grouped, and an element with the
+// same key fields already exists, r is aggregated into it. Parent
+// identifies the element which dst belongs to, if any.
+func (g *group) merge(dst reflect.Value, r *row, grouped bool, elemPtr bool, parent interface{}) {
+	pos := -1
+	var k interface{}
+	if grouped && g.hashable {
+		k = g.key(parent, r.value)
+		if i, ok := g.seen[k]; ok {
+			pos = i
+		}
+	} else if grouped {
+		// Keys can't be hashed, so compare against each element
+		for i := 0; i < dst.Len(); i++ {
+			e := reflect.Indirect(dst.Index(i))
+			if !e.IsValid() {
+				continue // Nil pointer
+			}
+			if g.equal(e, r.value) {
+				pos = i
+				break
+			}
+		}
+	}
+	if pos < 0 {
+		if elemPtr {
+			dst.Set(reflect.Append(dst, r.value.Addr()))
+		} else {
+			dst.Set(reflect.Append(dst, r.value))
+		}
+		pos = dst.Len() - 1
+		if grouped && g.hashable {
+			g.seen[k] = pos
+		}
+	}
+	g.apply(reflect.Indirect(dst.Index(pos)), r, elemKey{parent, pos})
+}
+
+// seed adds the elements already in the slice dst, and those of their
+// child slices, to seen, so that rows are grouped into them as they
+// are into elements added by merge. Parent identifies the element
+// which dst belongs to, if any.
+func (g *group) seed(dst reflect.Value, parent interface{}) {
+	for i := 0; i < dst.Len(); i++ {
+		e := reflect.Indirect(dst.Index(i))
+		if !e.IsValid() {
+			continue // Nil pointer
+		}
+		if g.hashable {
+			// The first of several equal elements is used, as
+			// when searching without a hash
+			k := g.key(parent, e)
+			if _, ok := g.seen[k]; !ok {
+				g.seen[k] = i
+			}
+		}
+		for _, c := range g.children {
+			c.seed(e.FieldByIndex(c.index), elemKey{parent, i})
+		}
+	}
+}
+
+// elemKey identifies an element of a slice in the result, by the
+// element which the slice belongs to, and its position.
+type elemKey struct {
+	parent interface{}
+	pos    int
+}
+
+// groupKey is a chain of key field values, starting from the element
+// which the slice belongs to.
+type groupKey struct {
+	prev interface{}
+	v    interface{}
+}
+
+// key returns a map key for the key fields of v, as a member of the
+// slice belonging to parent.
+func (g *group) key(parent interface{}, v reflect.Value) interface{} {
+	k := parent
+	for _, i := range g.keys {
+		k = groupKey{k, keyValue(v.FieldByIndex(g.fields[i]))}
+	}
+	return k
+}
+
+// keyValue returns a value which can be compared with == in place of
+// comparing v with reflect.DeepEqual. Pointers are followed, and times
+// are compared by instant.
+func keyValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Round(0).UTC()
+	}
+	return v.Interface()
+}
+
+// hashable reports whether keyValue of a field of type t can be used
+// as a map key.
+func hashable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+	var flat func(t reflect.Type) bool
+	flat = func(t reflect.Type) bool {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice,
+			reflect.Func, reflect.Chan, reflect.UnsafePointer:
+			return false
+		case reflect.Array:
+			return flat(t.Elem())
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				if !flat(t.Field(i).Type) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return flat(t)
+}
+
+// equal reports whether the key fields of x and y match.
+func (g *group) equal(x, y reflect.Value) bool {
+	for _, k := range g.keys {
+		a := x.FieldByIndex(g.fields[k]).Interface()
+		b := y.FieldByIndex(g.fields[k]).Interface()
+		if !reflect.DeepEqual(a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply adds the slice values of r to the struct e, identified by id.
+// NULL values are not added to aggregates, and child rows which are
+// entirely NULL are not added.
+func (g *group) apply(e reflect.Value, r *row, id interface{}) {
+	for _, k := range g.aggregates {
+		field := e.FieldByIndex(g.fields[k])
+		if new := r.temps[k].Elem(); !new.IsNil() {
+			field.Set(reflect.Append(field, new.Elem()))
+		}
+	}
+	for i, c := range g.children {
+		cr := r.children[i]
+		if c.null(cr) {
+			continue
+		}
+		c.fill(cr)
+		c.merge(e.FieldByIndex(c.index), cr, true, c.elemPtr, id)
+	}
+}
@@ -0,0 +1,234 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanNested(t *testing.T) {
+	const schema = `
+create table customer(id int, name text);
+create table orders(id int, customer int, total int);
+create table item(id int, ord int, name text);
+
+insert into customer values(1, 'alice'), (2, 'bob'), (3, 'carol');
+insert into orders values(10, 1, 100), (11, 1, 110), (20, 2, 200);
+insert into item values(100, 10, 'apple'), (101, 10, 'pear'), (110, 11, 'fig');
+`
+	db, err := sql.Open("sqlite3", ":memory:")
+	_panic(err)
+	defer db.Close()
+	for i, v := range strings.Split(schema, ";") {
+		if _, err := db.Exec(v); err != nil {
+			t.Fatalf("exec schema %d: %s:\n%s", i, err, v)
+		}
+	}
+
+	type item struct {
+		ID   int    `sql:"id"`
+		Name string `sql:"name"`
+	}
+	type order struct {
+		ID    int    `sql:"id"`
+		Total int    `sql:"total"`
+		Items []item `sql:"item"`
+	}
+	type customer struct {
+		ID     int      `sql:"id"`
+		Name   string   `sql:"name"`
+		Orders []*order `sql:"order"`
+	}
+
+	const query = `
+select c.id, c.name, o.id as order_id, o.total as order_total,
+       i.id as order_item_id, i.name as order_item_name
+from customer c
+left join orders o on o.customer = c.id
+left join item i on i.ord = o.id
+order by c.id, o.id, i.id`
+
+	t.Run("nested slices", func(t *testing.T) {
+		var dest []customer
+		if err := Scan(&dest, db, query); err != nil {
+			t.Fatal(err)
+		}
+		expect := []customer{
+			{1, "alice", []*order{
+				{10, 100, []item{{100, "apple"}, {101, "pear"}}},
+				{11, 110, []item{{110, "fig"}}},
+			}},
+			{2, "bob", []*order{{20, 200, nil}}},
+			{3, "carol", nil},
+		}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+
+	t.Run("nested rows are grouped into existing elements", func(t *testing.T) {
+		dest := []customer{{1, "alice", []*order{{10, 100, []item{{99, "plum"}}}}}}
+		if err := Scan(&dest, db, query+` limit 3`); err != nil {
+			t.Fatal(err)
+		}
+		expect := []customer{
+			{1, "alice", []*order{
+				{10, 100, []item{{99, "plum"}, {100, "apple"}, {101, "pear"}}},
+				{11, 110, []item{{110, "fig"}}},
+			}},
+		}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+
+	t.Run("scalar destination with nested slice", func(t *testing.T) {
+		var dest customer
+		if err := Scan(&dest, db, query+` limit 3`); err != nil {
+			t.Fatal(err)
+		}
+		if dest.Name != "alice" || len(dest.Orders) != 2 || len(dest.Orders[0].Items) != 2 {
+			t.Fatalf("unexpected: %#v", dest)
+		}
+	})
+
+	t.Run("unmatched prefixed column causes error", func(t *testing.T) {
+		var dest []customer
+		err := Scan(&dest, db, `select id, name, 1 as order_nope from customer`)
+		if err == nil || err.Error() != "no field for column order_nope" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestScanGroupKeys(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	_panic(err)
+	defer db.Close()
+	for i, v := range strings.Split(schema, ";") {
+		if _, err := db.Exec(v); err != nil {
+			t.Fatalf("exec schema %d: %s:\n%s", i, err, v)
+		}
+	}
+	const query = `select c, b from A order by b`
+
+	t.Run("pointer keys", func(t *testing.T) {
+		var dest []struct {
+			C *string `sql:"c"`
+			B []int   `sql:"b"`
+		}
+		if err := Scan(&dest, db, query); err != nil {
+			t.Fatal(err)
+		}
+		if len(dest) != 2 || *dest[0].C != "red" || !reflect.DeepEqual([]int{1, 2}, dest[0].B) {
+			t.Fatalf("unexpected rows: %#v", dest)
+		}
+	})
+
+	t.Run("rows are grouped into existing elements", func(t *testing.T) {
+		type g struct {
+			C string `sql:"c"`
+			B []int  `sql:"b"`
+		}
+		dest := []g{{"red", []int{0}}}
+		if err := Scan(&dest, db, query); err != nil {
+			t.Fatal(err)
+		}
+		expect := []g{{"red", []int{0, 1, 2}}, {"blue", []int{3}}}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+
+	t.Run("nil elements are skipped", func(t *testing.T) {
+		type g struct {
+			C interface{} `sql:"c"`
+			B []int       `sql:"b"`
+		}
+		dest := []*g{nil}
+		if err := Scan(&dest, db, query); err != nil {
+			t.Fatal(err)
+		}
+		if len(dest) != 3 || dest[0] != nil || !reflect.DeepEqual([]int{1, 2}, dest[1].B) {
+			t.Fatalf("unexpected rows: %#v", dest)
+		}
+	})
+
+	t.Run("unhashable keys", func(t *testing.T) {
+		var dest []struct {
+			C interface{} `sql:"c"`
+			B []int       `sql:"b"`
+		}
+		if err := Scan(&dest, db, query); err != nil {
+			t.Fatal(err)
+		}
+		if len(dest) != 2 || !reflect.DeepEqual([]int{1, 2}, dest[0].B) || !reflect.DeepEqual([]int{3}, dest[1].B) {
+			t.Fatalf("unexpected rows: %#v", dest)
+		}
+	})
+}
+
+func TestHashable(t *testing.T) {
+	type flat struct {
+		A int
+		B [2]string
+	}
+	type deep struct {
+		A *int
+	}
+	tests := []struct {
+		v      interface{}
+		expect bool
+	}{
+		{0, true},
+		{"", true},
+		{new(string), true},
+		{flat{}, true},
+		{time.Time{}, true},
+		{deep{}, false},
+		{[]byte{}, false},
+		{map[string]int{}, false},
+		{new(interface{}), false},
+	}
+	for _, tt := range tests {
+		if got := hashable(reflect.TypeOf(tt.v)); got != tt.expect {
+			t.Errorf("hashable(%T) expected %v, got %v", tt.v, tt.expect, got)
+		}
+	}
+}
+
+func BenchmarkScanAggregate(b *testing.B) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	_panic(err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`create table A(a int, b int)`)
+	_panic(err)
+	type row struct {
+		A int `sql:"a"`
+		B int `sql:"b"`
+	}
+	rows := make([]row, 50000)
+	for i := range rows {
+		rows[i] = row{i / 5, i}
+	}
+	_, err = InsertBatchTx(context.Background(), db, "A", rows, 0)
+	_panic(err)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var dest []struct {
+			A int   `sql:"a"`
+			B []int `sql:"b"`
+		}
+		err = Scan(&dest, db, `select a, b from A`)
+		_panic(err)
+		if len(dest) != len(rows)/5 {
+			panic("incorrect number of rows returned")
+		}
+	}
+}
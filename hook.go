@@ -0,0 +1,107 @@
+package sqlh
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Event describes a statement run through a DB or Tx.
+type Event struct {
+	Statement string
+	Args      []interface{}
+	// The following are set once the statement has run.
+	Duration     time.Duration
+	RowsAffected int64 // -1 for queries, or if unknown
+	Err          error
+}
+
+// Hook observes the statements run through a DB or Tx, e.g. for
+// logging, tracing or metrics. Hooks are given in Options.
+//
+// For queries, After is called once the query has returned its rows,
+// so Duration does not include reading them.
+type Hook interface {
+	// Before is called before a statement is run. The context it
+	// returns is used to run the statement, and is given to After.
+	Before(ctx context.Context, e *Event) context.Context
+	// After is called once the statement has run.
+	After(ctx context.Context, e *Event)
+}
+
+// run calls fn to run a statement between the hooks of o, with any
+// named parameters bound. Fn returns the number of rows affected, or
+// -1. If the parameters can't be bound, fn is not called, and the
+// error is logged and given to the hooks in its place.
+func (o Options) run(ctx context.Context, query string, args []interface{}, fn func(ctx context.Context, query string, args []interface{}) (int64, error)) error {
+	var bindErr error
+	if p, ok := params(args); ok {
+		if q, a, err := p.bind(o.config(), query, 0); err != nil {
+			bindErr = err
+		} else {
+			query, args = q, a
+		}
+	}
+	call := func(ctx context.Context) (int64, error) {
+		if bindErr != nil {
+			return -1, bindErr
+		}
+		return fn(ctx, query, args)
+	}
+	o.log(query, args, bindErr)
+	if len(o.Hooks) == 0 {
+		_, err := call(ctx)
+		return err
+	}
+	e := &Event{Statement: query, Args: args}
+	ctxs := make([]context.Context, len(o.Hooks))
+	for i, h := range o.Hooks {
+		ctx = h.Before(ctx, e)
+		ctxs[i] = ctx
+	}
+	start := time.Now()
+	e.RowsAffected, e.Err = call(ctx)
+	e.Duration = time.Since(start)
+	for i := len(o.Hooks) - 1; i >= 0; i-- {
+		o.Hooks[i].After(ctxs[i], e)
+	}
+	return e.Err
+}
+
+// Recorder is a Hook which keeps every event, for use in tests.
+//
+//   r := &Recorder{}
+//   db := NewDB(sqlDB, Options{Hooks: []Hook{r}})
+//   ...
+//   events := r.Events()
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Before implements Hook.
+func (r *Recorder) Before(ctx context.Context, e *Event) context.Context {
+	return ctx
+}
+
+// After implements Hook.
+func (r *Recorder) After(ctx context.Context, e *Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, *e)
+}
+
+// Events returns the events recorded so far, in the order the
+// statements finished.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// Reset forgets all recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
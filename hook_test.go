@@ -0,0 +1,110 @@
+package sqlh
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type ctxKey struct{}
+
+// tagHook stores its name in the context, and records the value seen
+// in After.
+type tagHook struct {
+	name string
+	seen *[]string
+}
+
+func (h tagHook) Before(ctx context.Context, e *Event) context.Context {
+	*h.seen = append(*h.seen, "before "+h.name)
+	return context.WithValue(ctx, ctxKey{}, h.name)
+}
+
+func (h tagHook) After(ctx context.Context, e *Event) {
+	*h.seen = append(*h.seen, "after "+ctx.Value(ctxKey{}).(string))
+}
+
+func TestHooks(t *testing.T) {
+	type row struct {
+		ID   int    `sql:"id"`
+		Name string `sql:"name"`
+	}
+
+	r := &Recorder{}
+	var seen []string
+	db, err := Open("sqlite3", ":memory:", Options{
+		Dialect: SQLite,
+		Hooks:   []Hook{r, tagHook{"a", &seen}, tagHook{"b", &seen}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`create table T(id integer primary key, name text)`); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("exec records rows affected", func(t *testing.T) {
+		r.Reset()
+		if _, err := db.Insert("T", []row{{1, "one"}, {2, "two"}}); err != nil {
+			t.Fatal(err)
+		}
+		events := r.Events()
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got: %#v", events)
+		}
+		e := events[0]
+		if e.Statement != "insert into T(id, name) values(?1, ?2), (?3, ?4)" {
+			t.Fatalf("unexpected statement: %s", e.Statement)
+		}
+		if !reflect.DeepEqual([]interface{}{1, "one", 2, "two"}, e.Args) {
+			t.Fatalf("unexpected args: %#v", e.Args)
+		}
+		if e.RowsAffected != 2 || e.Err != nil {
+			t.Fatalf("unexpected event: %#v", e)
+		}
+	})
+
+	t.Run("query records errors", func(t *testing.T) {
+		r.Reset()
+		var dest []row
+		if err := db.Scan(&dest, `select * from Missing`); err == nil {
+			t.Fatal("expected error")
+		}
+		events := r.Events()
+		if len(events) != 1 || events[0].Err == nil || events[0].RowsAffected != -1 {
+			t.Fatalf("unexpected events: %#v", events)
+		}
+	})
+
+	t.Run("query row and transactions", func(t *testing.T) {
+		r.Reset()
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		if err := tx.QueryRow(`select count(*) from T`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		events := r.Events()
+		if n != 2 || len(events) != 1 || events[0].Statement != `select count(*) from T` {
+			t.Fatalf("unexpected events: %#v", events)
+		}
+	})
+
+	t.Run("hooks are nested", func(t *testing.T) {
+		seen = nil
+		if _, err := db.Exec(`delete from T where id = ?1`, 3); err != nil {
+			t.Fatal(err)
+		}
+		expect := []string{"before a", "before b", "after b", "after a"}
+		if !reflect.DeepEqual(expect, seen) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, seen)
+		}
+	})
+}
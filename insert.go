@@ -1,6 +1,7 @@
 package sqlh
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -18,36 +19,54 @@ import (
 //
 //   res, err := Insert(db, "X", values)
 //   // = db.Exec(`insert into X(id, b) values($1, $2), ($3, $4)`, 1, "test", 2, "test")
+//
+// Values may also be given as a map[string]interface{}, or a slice of
+// maps with the same keys, which are inserted in sorted order. Keys are
+// written into the statement as they are, so maps from untrusted
+// input should first be checked with AllowColumns.
+//
+// Placeholders are written in DefaultDialect.
 func Insert(db Executor, table string, values interface{}) (sql.Result, error) {
-	i, err := insert(table, values)
+	i, err := insert(configOf(db), table, values)
 	if err != nil {
 		return nil, err
 	}
 	return db.Exec(i.statement, i.args...)
 }
 
+// InsertContext is like Insert, but executes the statement with a
+// context.
+func InsertContext(ctx context.Context, db ExecutorContext, table string, values interface{}) (sql.Result, error) {
+	i, err := insert(configOf(db), table, values)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, i.statement, i.args...)
+}
+
 type preInsert struct {
 	statement string
 	args      []interface{}
+	columns   []string
 }
 
-func insert(table string, values interface{}) (*preInsert, error) {
+func insert(c config, table string, values interface{}) (*preInsert, error) {
 	var vs []reflect.Value
 
-	switch k := reflect.ValueOf(values).Kind(); k {
-	case reflect.Struct:
-		vs = append(vs, reflect.ValueOf(values))
-	case reflect.Slice:
-		v := reflect.ValueOf(values)
+	v := reflect.ValueOf(values)
+	switch k := v.Kind(); {
+	case k == reflect.Struct, k == reflect.Map && isMap(v.Type()):
+		vs = append(vs, v)
+	case k == reflect.Slice:
 		for i := 0; i < v.Len(); i++ {
 			w := v.Index(i)
-			if w.Kind() != reflect.Struct {
-				return nil, fmt.Errorf("values must be struct or []struct, not: %v", k)
+			if w.Kind() != reflect.Struct && !isMap(w.Type()) {
+				return nil, fmt.Errorf("values must be struct, map or slice of them, not: %v", w.Type())
 			}
 			vs = append(vs, w)
 		}
 	default:
-		return nil, fmt.Errorf("values must be struct or []struct, not: %v", k)
+		return nil, fmt.Errorf("values must be struct, map or slice of them, not: %v", k)
 	}
 
 	if len(vs) < 1 {
@@ -60,68 +79,65 @@ func insert(table string, values interface{}) (*preInsert, error) {
 		}
 	}
 
-	// Build columns and values arrays
+	// Build up set of columns we are using, and set of arguments for
+	// statement
 	var columns []string
-	var columnIdx [][]int
-
-	// Build up set of columns we are using
-	var recurseFields func(t reflect.Type, index []int)
-	recurseFields = func(t reflect.Type, index []int) {
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			if field.Anonymous {
-				recurseFields(field.Type, append(index, field.Index...))
-				continue
-			}
-			tag, ok := field.Tag.Lookup("sql")
+	var argset []interface{}
+	if vs[0].Kind() == reflect.Map {
+		columns = mapColumns(vs[0])
+		for i, v := range vs {
+			args, ok := mapValues(v, columns)
 			if !ok {
-				continue // Ignore untagged
+				return nil, fmt.Errorf("values %d have different columns to values 0", i)
 			}
-			name, ignore := parseTag(tag, "insert")
-			if ignore {
-				continue // Explicitly ignored
+			argset = append(argset, args...)
+		}
+	} else {
+		var columnIdx [][]int
+		columns, columnIdx = insertFields(c, vs)
+		argset = make([]interface{}, len(columns)*len(vs))
+		for i, v := range vs {
+			for j := range columns {
+				value := v.FieldByIndex(columnIdx[j])
+				x := i * len(columns)
+				argset[x+j] = value.Interface()
 			}
-			columns = append(columns, name)
-			columnIdx = append(columnIdx, append(index, field.Index...))
 		}
 	}
-	recurseFields(vs[0].Type(), []int{})
 
 	if len(columns) < 1 {
 		return nil, fmt.Errorf("no columns available for insert")
 	}
 
-	// Build set of arguments for statement
-	argset := make([]interface{}, len(columns)*len(vs))
-	for i, v := range vs {
-		for j := range columns {
-			value := v.FieldByIndex(columnIdx[j])
-			x := i * len(columns)
-			argset[x+j] = value.Interface()
-		}
+	valueList := ""
+	sep := ""
+	for i := range vs {
+		valueList += sep + "(" + c.dialect.placeholders(i*len(columns), len(columns)) + ")"
+		sep = ", "
 	}
-
-	placeholders := repeatWithIndex("$", ", ", len(columns))
-	value := "(" + placeholders + ")"
-	valueList := repeat(value, ", ", len(argset)/len(columns))
 	columnList := strings.Join(columns, ", ")
 	statement := fmt.Sprintf("insert into %s(%s) values%s", table, columnList, valueList)
 
 	return &preInsert{
 		statement: statement,
 		args:      argset,
+		columns:   columns,
 	}, nil
 }
 
-func repeatWithIndex(prefix, separator string, n int) string {
-	if n < 0 {
-		panic("n < 0")
-	}
-	v := ""
-	ssep := ""
-	for i := 1; i <= n; i++ {
-		v += ssep + fmt.Sprintf("%v%v", prefix, i)
-		ssep = separator
+// insertFields returns the columns and field indexes of the struct
+// values vs to insert. Fields which are unset in every value, such as
+// an unset Nullable, are left out.
+func insertFields(c config, vs []reflect.Value) (columns []string, index [][]int) {
+	all, allIdx := taggedFields(c, vs[0].Type(), "insert")
+	for j, name := range all {
+		for _, v := range vs {
+			if !unset(v.FieldByIndex(allIdx[j])) {
+				columns = append(columns, name)
+				index = append(index, allIdx[j])
+				break
+			}
+		}
 	}
-	return v
+	return columns, index
 }
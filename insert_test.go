@@ -1,7 +1,9 @@
 package sqlh
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -33,7 +35,7 @@ func TestInsert(t *testing.T) {
 	sep := ""
 	for i := range rows {
 		values = append(values, rows[i].A, rows[i].B)
-		statement += sep + "($1, $2)"
+		statement += sep + fmt.Sprintf("($%d, $%d)", 2*i+1, 2*i+2)
 		sep = ", "
 	}
 
@@ -46,7 +48,7 @@ func TestInsert(t *testing.T) {
 	}
 
 	t.Run("insert scalar", func(t *testing.T) {
-		x, err := insert("X", rows[0])
+		x, err := insert(config{dialect: Postgres}, "X", rows[0])
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -63,7 +65,7 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("insert slice", func(t *testing.T) {
-		x, err := insert("X", rows)
+		x, err := insert(config{dialect: Postgres}, "X", rows)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -78,8 +80,19 @@ func TestInsert(t *testing.T) {
 		}
 	})
 
+	t.Run("insert with unnumbered dialect", func(t *testing.T) {
+		x, err := insert(config{dialect: MySQL}, "X", rows[:2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into X(a, b) values(?, ?), (?, ?)`
+		if statement != x.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, x.statement)
+		}
+	})
+
 	t.Run("view SQL statement and values before exec", func(t *testing.T) {
-		x, err := insert("X", rows)
+		x, err := insert(config{dialect: Postgres}, "X", rows)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -108,6 +121,14 @@ func TestInsert(t *testing.T) {
 		}
 	})
 
+	t.Run("exec with cancelled context fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := InsertContext(ctx, db, "X", rows); err != context.Canceled {
+			t.Fatalf("expected %v, got: %v", context.Canceled, err)
+		}
+	})
+
 }
 
 func TestInsertExplicitIgnore(t *testing.T) {
@@ -132,3 +153,16 @@ func TestInsertExplicitIgnore(t *testing.T) {
 		t.Fatalf("id should be 1, got %d", x.ID)
 	}
 }
+
+func BenchmarkInsertStatement(b *testing.B) {
+	type row struct {
+		A int    `sql:"a"`
+		B string `sql:"b"`
+		C string `sql:"c/insert"`
+	}
+	rows := make([]row, 100)
+	for i := 0; i < b.N; i++ {
+		_, err := insert(config{dialect: Postgres}, "X", rows)
+		_panic(err)
+	}
+}
@@ -0,0 +1,138 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Rows is a cursor over the results of a query, which maps one row at
+// a time into a value of type T. It is created by Iter.
+type Rows[T any] struct {
+	ctx       context.Context
+	rows      *sql.Rows
+	value     T
+	receivers []interface{}
+	err       error
+}
+
+// Iter runs a query, returning a cursor which maps each row into a T
+// using the same rules as Scan, without holding the whole result set
+// in memory.
+//
+//   rows := Iter[row](db, `select a, b from C`)
+//   defer rows.Close()
+//   for rows.Next() {
+//     r := rows.Value()
+//     ...
+//   }
+//   if err := rows.Err(); err != nil {
+//     ...
+//   }
+//
+// Unlike Scan, rows are not grouped, so slice fields and slices of
+// structs are not supported by Iter.
+func Iter[T any](db Querist, query string, args ...interface{}) *Rows[T] {
+	c := configOf(db)
+	query, args, err := prepare(c, query, args, 0)
+	if err != nil {
+		return newRows[T](context.Background(), c, nil, err)
+	}
+	rows, err := db.Query(query, args...)
+	return newRows[T](context.Background(), c, rows, err)
+}
+
+// IterContext is like Iter, but runs the query with a context. If the
+// context is cancelled, iteration stops with the context's error.
+func IterContext[T any](ctx context.Context, db QueristContext, query string, args ...interface{}) *Rows[T] {
+	c := configOf(db)
+	query, args, err := prepare(c, query, args, 0)
+	if err != nil {
+		return newRows[T](ctx, c, nil, err)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	return newRows[T](ctx, c, rows, err)
+}
+
+func newRows[T any](ctx context.Context, c config, rows *sql.Rows, err error) *Rows[T] {
+	r := &Rows[T]{ctx: ctx, rows: rows, err: err}
+	if err != nil {
+		return r
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		r.fail(err)
+		return r
+	}
+
+	// Receivers point into r.value, which is reset before each row
+	v := reflect.ValueOf(&r.value).Elem()
+	r.receivers = make([]interface{}, len(columns))
+	if t := v.Type(); isRow(t) {
+		index, err := columnFields(c, t, columns)
+		if err != nil {
+			r.fail(err)
+			return r
+		}
+		for i := range columns {
+			r.receivers[i] = v.FieldByIndex(index[i]).Addr().Interface()
+		}
+	} else if len(columns) != 1 {
+		r.fail(fmt.Errorf("can't scan %d columns into %s", len(columns), t))
+	} else {
+		r.receivers[0] = v.Addr().Interface()
+	}
+	return r
+}
+
+// Next prepares the next row to be read with Value. It returns false
+// when there are no more rows or an error occurred, after which the
+// cursor is closed.
+func (r *Rows[T]) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	if err := r.ctx.Err(); err != nil {
+		r.fail(err)
+		return false
+	}
+	if !r.rows.Next() {
+		r.fail(r.rows.Err())
+		return false
+	}
+	var zero T
+	r.value = zero
+	if err := r.rows.Scan(r.receivers...); err != nil {
+		r.fail(err)
+		return false
+	}
+	return true
+}
+
+// Value returns the current row.
+func (r *Rows[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error, if any, which stopped iteration.
+func (r *Rows[T]) Err() error {
+	return r.err
+}
+
+// Close closes the cursor. It is safe to call Close more than once,
+// and after Next has returned false.
+func (r *Rows[T]) Close() error {
+	if r.rows == nil {
+		return nil
+	}
+	return r.rows.Close()
+}
+
+// fail records err, if any, and closes the cursor.
+func (r *Rows[T]) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+	r.rows.Close()
+}
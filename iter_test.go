@@ -0,0 +1,113 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestIter(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	_panic(err)
+	defer db.Close()
+	for i, v := range strings.Split(schema, ";") {
+		if _, err := db.Exec(v); err != nil {
+			t.Fatalf("exec schema %d: %s:\n%s", i, err, v)
+		}
+	}
+
+	t.Run("iterate structs", func(t *testing.T) {
+		rows := Iter[a](db, `select * from A`)
+		defer rows.Close()
+		var dest []a
+		for rows.Next() {
+			dest = append(dest, rows.Value())
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+
+	t.Run("iterate base type", func(t *testing.T) {
+		rows := Iter[int](db, `select b from A`)
+		defer rows.Close()
+		sum := 0
+		for rows.Next() {
+			sum += rows.Value()
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if sum != 6 {
+			t.Fatalf("expected 6, got: %d", sum)
+		}
+	})
+
+	t.Run("iterate scanner structs", func(t *testing.T) {
+		rows := Iter[sql.NullString](db, `select a from A`)
+		defer rows.Close()
+		var dest []string
+		for rows.Next() {
+			dest = append(dest, rows.Value().String)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([]string{"one", "two", "three"}, dest) {
+			t.Fatalf("unexpected rows: %#v", dest)
+		}
+		n := Iter[Nullable[int]](db, `select b from A`)
+		defer n.Close()
+		if !n.Next() || n.Value() != NewNullable(1) {
+			t.Fatalf("unexpected row %#v: %v", n.Value(), n.Err())
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		rows := Iter[a](db, `select * from Nope`)
+		defer rows.Close()
+		if rows.Next() {
+			t.Fatal("expected no rows")
+		}
+		if rows.Err() == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("unmatched columns cause error", func(t *testing.T) {
+		type d struct {
+			A string `sql:"a"`
+		}
+		rows := Iter[d](db, `select * from A`)
+		defer rows.Close()
+		for rows.Next() {
+			t.Fatal("expected no rows")
+		}
+		test := regexp.MustCompile(`^no field for column b$`)
+		if err := rows.Err(); err == nil || !test.MatchString(err.Error()) {
+			t.Fatalf("expected match for %v, got: %v", test, err)
+		}
+	})
+
+	t.Run("cancelled context stops iteration", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		rows := IterContext[a](ctx, db, `select * from A`)
+		defer rows.Close()
+		if !rows.Next() {
+			t.Fatal(rows.Err())
+		}
+		cancel()
+		if rows.Next() {
+			t.Fatal("expected iteration to stop")
+		}
+		if err := rows.Err(); err != context.Canceled {
+			t.Fatalf("expected %v, got: %v", context.Canceled, err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Get scans the row identified by keys into dest, which must be a
+// pointer to a struct. The keys are given in the order of the fields
+// tagged with the pk option.
+//
+//   type row struct{
+//       Id int `sql:"id,pk"`
+//       Name string `sql:"name"`
+//   }
+//   var dest row
+//   err := Get(db, &dest, "X", 1)
+//   // = Scan(&dest, db, `SELECT id, name FROM X WHERE id = $1`, 1)
+//
+// If there is no such row, sql.ErrNoRows is returned.
+func Get(db Querist, dest interface{}, table string, keys ...interface{}) error {
+	g, err := get(configOf(db), dest, table, keys)
+	if err != nil {
+		return err
+	}
+	return Scan(dest, db, g.statement, g.args...)
+}
+
+// GetContext is like Get, but runs the query with a context.
+func GetContext(ctx context.Context, db QueristContext, dest interface{}, table string, keys ...interface{}) error {
+	g, err := get(configOf(db), dest, table, keys)
+	if err != nil {
+		return err
+	}
+	return ScanContext(ctx, dest, db, g.statement, g.args...)
+}
+
+// UpdateByKey runs an SQL UPDATE query for the row identified by the
+// key fields of value. Other fields are set as described for Update.
+//
+//   res, err := UpdateByKey(db, "X", row{Id: 1, Name: "updated"})
+//   // = db.Exec(`UPDATE X SET name = $1 WHERE id = $2`, "updated", 1)
+func UpdateByKey(db Executor, table string, value interface{}) (sql.Result, error) {
+	u, err := updateByKey(configOf(db), table, value)
+	if err != nil {
+		return nil, err
+	}
+	return db.Exec(u.statement, u.args...)
+}
+
+// UpdateByKeyContext is like UpdateByKey, but executes the statement
+// with a context.
+func UpdateByKeyContext(ctx context.Context, db ExecutorContext, table string, value interface{}) (sql.Result, error) {
+	u, err := updateByKey(configOf(db), table, value)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, u.statement, u.args...)
+}
+
+// Exists reports whether there is a row matching the key fields of
+// value.
+//
+//   ok, err := Exists(db, "X", row{Id: 1})
+//   // = Scan(&n, db, `SELECT COUNT(*) FROM X WHERE id = $1`, 1)
+func Exists(db Querist, table string, value interface{}) (bool, error) {
+	e, err := exists(configOf(db), table, value)
+	if err != nil {
+		return false, err
+	}
+	var n int
+	err = Scan(&n, db, e.statement, e.args...)
+	return n > 0, err
+}
+
+// ExistsContext is like Exists, but runs the query with a context.
+func ExistsContext(ctx context.Context, db QueristContext, table string, value interface{}) (bool, error) {
+	e, err := exists(configOf(db), table, value)
+	if err != nil {
+		return false, err
+	}
+	var n int
+	err = ScanContext(ctx, &n, db, e.statement, e.args...)
+	return n > 0, err
+}
+
+type preSelect struct {
+	statement string
+	args      []interface{}
+}
+
+func get(c config, dest interface{}, table string, keys []interface{}) (*preSelect, error) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dest is not a pointer to a struct")
+	}
+	t := v.Elem().Type()
+	columns, _ := keyFields(c, t)
+	if len(columns) < 1 {
+		return nil, fmt.Errorf("no key fields in %v", t)
+	}
+	if len(keys) != len(columns) {
+		return nil, fmt.Errorf("expected %d keys, got %d", len(columns), len(keys))
+	}
+	selected, _ := taggedFields(c, t, "select")
+	stmt := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(selected, ", "), table, keyClause(c, columns))
+	return &preSelect{
+		statement: stmt,
+		args:      keys,
+	}, nil
+}
+
+func updateByKey(c config, table string, value interface{}) (*preUpdate, error) {
+	where, args, err := keyWhere(c, value)
+	if err != nil {
+		return nil, err
+	}
+	set, vals, err := updateSet(c, reflect.ValueOf(value), setMode{skipKeys: true})
+	if err != nil {
+		return nil, err
+	}
+	return buildUpdate(c, table, set, vals, where, args...)
+}
+
+func exists(c config, table string, value interface{}) (*preSelect, error) {
+	where, args, err := keyWhere(c, value)
+	if err != nil {
+		return nil, err
+	}
+	return &preSelect{
+		statement: fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", table, where),
+		args:      args,
+	}, nil
+}
+
+// keyWhere builds a where clause matching the key fields of value,
+// along with its arguments.
+func keyWhere(c config, value interface{}) (string, []interface{}, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Struct {
+		return "", nil, fmt.Errorf("value was not a struct: %v", v.Type())
+	}
+	columns, index := keyFields(c, v.Type())
+	if len(columns) < 1 {
+		return "", nil, fmt.Errorf("no key fields in %v", v.Type())
+	}
+	var args []interface{}
+	for i := range columns {
+		args = append(args, v.FieldByIndex(index[i]).Interface())
+	}
+	return keyClause(c, columns), args, nil
+}
+
+// keyClause returns a where clause matching each of the key columns
+// to an argument.
+func keyClause(c config, columns []string) string {
+	var where []string
+	for i, name := range columns {
+		where = append(where, name+" = "+c.dialect.placeholder(i+1))
+	}
+	return strings.Join(where, " AND ")
+}
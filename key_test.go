@@ -0,0 +1,111 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	type e struct {
+		A int `sql:"a,pk"`
+	}
+	type row struct {
+		e
+		B int    `sql:"b,pk"`
+		C string `sql:"c"`
+		D string `sql:"-"`
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`create table T(a int, b int, c text)`); err != nil {
+		t.Fatal(err)
+	}
+	rows := []row{{e{1}, 1, "one", ""}, {e{1}, 2, "two", ""}}
+	if _, err := Insert(db, "T", rows); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("get statement", func(t *testing.T) {
+		var dest row
+		g, err := get(config{dialect: Postgres}, &dest, "T", []interface{}{1, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preSelect{
+			statement: `SELECT a, b, c FROM T WHERE a = $1 AND b = $2`,
+			args:      []interface{}{1, 2},
+		}
+		if !reflect.DeepEqual(exp, *g) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *g)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var dest row
+		if err := Get(db, &dest, "T", 1, 2); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rows[1], dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", rows[1], dest)
+		}
+		if err := Get(db, &dest, "T", 2, 2); err != sql.ErrNoRows {
+			t.Fatalf("expected %v, got: %v", sql.ErrNoRows, err)
+		}
+	})
+
+	t.Run("get with wrong number of keys fails", func(t *testing.T) {
+		var dest row
+		if err := Get(db, &dest, "T", 1); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("update by key statement", func(t *testing.T) {
+		u, err := updateByKey(config{dialect: Postgres}, "T", row{e{1}, 2, "updated", ""})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preUpdate{
+			statement: `UPDATE T SET c = $1 WHERE a = $2 AND b = $3`,
+			args:      []interface{}{"updated", 1, 2},
+		}
+		if !reflect.DeepEqual(exp, *u) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
+		}
+	})
+
+	t.Run("update by key", func(t *testing.T) {
+		if _, err := UpdateByKey(db, "T", row{e{1}, 2, "updated", ""}); err != nil {
+			t.Fatal(err)
+		}
+		var dest []row
+		if err := Scan(&dest, db, `select * from T order by b`); err != nil {
+			t.Fatal(err)
+		}
+		expect := []row{{e{1}, 1, "one", ""}, {e{1}, 2, "updated", ""}}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		ok, err := Exists(db, "T", row{e: e{1}, B: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected row to exist")
+		}
+		ok, err = Exists(db, "T", row{e: e{1}, B: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("expected row not to exist")
+		}
+	})
+}
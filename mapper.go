@@ -0,0 +1,55 @@
+package sqlh
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Mapper maps the names of exported struct fields without a `sql` tag
+// to column names, so that they can be used without tagging every
+// field. Tagged fields always use the name in their tag.
+type Mapper struct {
+	f func(field string) string
+}
+
+// NewMapper returns a Mapper which maps field names to columns with f.
+func NewMapper(f func(field string) string) *Mapper {
+	return &Mapper{f: f}
+}
+
+var (
+	// SnakeCase maps a field such as UserID to user_id.
+	SnakeCase = NewMapper(snakeCase)
+	// LowerCase maps a field such as UserID to userid.
+	LowerCase = NewMapper(strings.ToLower)
+	// ExactCase maps a field such as UserID to UserID.
+	ExactCase = NewMapper(func(field string) string { return field })
+)
+
+// DefaultMapper is used to map untagged fields in the package level
+// functions. If it is nil, untagged fields are ignored. It should be
+// set before any statements are run.
+var DefaultMapper *Mapper
+
+// column returns the column name for a field.
+func (m *Mapper) column(field string) string {
+	return m.f(field)
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping initialisms
+// together. E.g., HTTPServerID becomes http_server_id.
+func snakeCase(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			next := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
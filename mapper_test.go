@@ -0,0 +1,90 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"A":            "a",
+		"ID":           "id",
+		"UserID":       "user_id",
+		"HTTPServerID": "http_server_id",
+		"CreatedAt":    "created_at",
+		"Address2Line": "address2_line",
+		"already_done": "already_done",
+	}
+	for in, expect := range tests {
+		if got := snakeCase(in); got != expect {
+			t.Errorf("snakeCase(%#v) expected %#v, got %#v", in, expect, got)
+		}
+	}
+}
+
+func TestMapper(t *testing.T) {
+	type e struct {
+		UserID int
+	}
+	type row struct {
+		e
+		FullName string
+		Other    string `sql:"nick/insert"` // Tags override the mapper
+		Ignored  string `sql:"-"`
+		hidden   string
+	}
+
+	t.Run("insert statement", func(t *testing.T) {
+		c := config{dialect: Postgres, mapper: SnakeCase}
+		i, err := insert(c, "T", row{e{1}, "a", "b", "c", "d"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into T(user_id, full_name) values($1, $2)`
+		if statement != i.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, i.statement)
+		}
+		c.mapper = LowerCase
+		if i, err = insert(c, "T", row{}); err != nil {
+			t.Fatal(err)
+		}
+		statement = `insert into T(userid, fullname) values($1, $2)`
+		if statement != i.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, i.statement)
+		}
+	})
+
+	t.Run("untagged fields ignored without mapper", func(t *testing.T) {
+		if _, err := insert(config{dialect: Postgres}, "T", row{}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		defer func(m *Mapper) { DefaultMapper = m }(DefaultMapper)
+		DefaultMapper = SnakeCase
+
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`create table T(user_id int, full_name text, nick text)`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Insert(db, "T", row{e{1}, "Full Name", "", "", ""}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`update T set nick = 'nick'`); err != nil {
+			t.Fatal(err)
+		}
+		var dest row
+		if err := Scan(&dest, db, `select * from T`); err != nil {
+			t.Fatal(err)
+		}
+		expect := row{e{1}, "Full Name", "nick", "", ""}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package sqlh
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AllowColumns returns an error if map m, to be given to Insert or
+// Update, has a key which is not one of the allowed columns.
+//
+//   var body map[string]interface{}
+//   json.NewDecoder(r.Body).Decode(&body)
+//   if err := AllowColumns(body, "name", "email"); err != nil {
+//     ...
+//   }
+//   res, err := Update(db, "users", body, "id = $1", id)
+func AllowColumns(m map[string]interface{}, columns ...string) error {
+	for _, k := range mapColumns(reflect.ValueOf(m)) {
+		if !contains(columns, k) {
+			return fmt.Errorf("column not allowed: %s", k)
+		}
+	}
+	return nil
+}
+
+// isMap reports whether t is a map with string keys, which can be
+// given in place of a struct to Insert and Update.
+func isMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// mapColumns returns the keys of map v, sorted.
+func mapColumns(v reflect.Value) []string {
+	columns := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		columns = append(columns, k.String())
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// mapValues returns the values of map v for the given columns, and
+// whether v has exactly those keys.
+func mapValues(v reflect.Value, columns []string) ([]interface{}, bool) {
+	if v.Len() != len(columns) {
+		return nil, false
+	}
+	vals := make([]interface{}, len(columns))
+	for i, name := range columns {
+		x := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !x.IsValid() {
+			return nil, false
+		}
+		vals[i] = x.Interface()
+	}
+	return vals, true
+}
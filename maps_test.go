@@ -0,0 +1,107 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestMaps(t *testing.T) {
+	c := config{dialect: Postgres}
+
+	t.Run("insert map", func(t *testing.T) {
+		i, err := insert(c, "X", map[string]interface{}{"b": "two", "a": 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preInsert{
+			statement: "insert into X(a, b) values($1, $2)",
+			args:      []interface{}{1, "two"},
+			columns:   []string{"a", "b"},
+		}
+		if !reflect.DeepEqual(exp, *i) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *i)
+		}
+	})
+
+	t.Run("insert slice of maps", func(t *testing.T) {
+		i, err := insert(c, "X", []map[string]interface{}{{"a": 1, "b": nil}, {"b": "x", "a": 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.statement != "insert into X(a, b) values($1, $2), ($3, $4)" {
+			t.Fatalf("unexpected statement: %s", i.statement)
+		}
+		if !reflect.DeepEqual([]interface{}{1, nil, 2, "x"}, i.args) {
+			t.Fatalf("unexpected args: %#v", i.args)
+		}
+	})
+
+	t.Run("insert maps with different keys fails", func(t *testing.T) {
+		_, err := insert(c, "X", []map[string]interface{}{{"a": 1}, {"b": 2}})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("update map", func(t *testing.T) {
+		u, err := update(c, "X", map[string]interface{}{"b": "", "a": nil}, "id = $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preUpdate{
+			statement: "UPDATE X SET a = $1, b = $2 WHERE id = $3",
+			args:      []interface{}{nil, "", 1},
+		}
+		if !reflect.DeepEqual(exp, *u) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
+		}
+	})
+
+	t.Run("upsert map", func(t *testing.T) {
+		u, err := upsert(c, "X", map[string]interface{}{"id": 1, "a": 2}, []string{"id"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.statement != "insert into X(a, id) values($1, $2) on conflict(id) do update set a = excluded.a" {
+			t.Fatalf("unexpected statement: %s", u.statement)
+		}
+	})
+
+	t.Run("allow columns", func(t *testing.T) {
+		m := map[string]interface{}{"a": 1, "b; drop table X": 2}
+		if err := AllowColumns(m, "a", "b"); err == nil || err.Error() != "column not allowed: b; drop table X" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := AllowColumns(map[string]interface{}{"a": 1}, "a", "b"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec(`create table X(id int, a int, b text)`); err != nil {
+			t.Fatal(err)
+		}
+		rows := []map[string]interface{}{{"id": 1, "a": 1, "b": "one"}, {"id": 2, "a": 2, "b": "two"}}
+		if _, err := InsertBatch(db, "X", rows, 3); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Update(db, "X", map[string]interface{}{"a": 0}, "id = ?", 2); err != nil {
+			t.Fatal(err)
+		}
+		var dest []map[string]interface{}
+		if err := Scan(&dest, db, `select a, b from X order by id`); err != nil {
+			t.Fatal(err)
+		}
+		exp := []map[string]interface{}{{"a": int64(1), "b": "one"}, {"a": int64(0), "b": "two"}}
+		if !reflect.DeepEqual(exp, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, dest)
+		}
+	})
+}
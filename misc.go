@@ -1,34 +1,111 @@
 package sqlh
 
 import (
+	"reflect"
 	"strings"
 )
 
-func repeat(s, sep string, n int) string {
-	if n < 0 {
-		panic("n < 0")
-	}
-	v := ""
-	ssep := ""
-	for i := 0; i < n; i++ {
-		v += ssep + s
-		ssep = sep
-	}
-	return v
-}
-
 // parseTag returns the column name and whether the field should be ignored
 // based on the context. Context being a string like insert, select, or update.
+//
+// Options may follow the column name, separated by commas, as in
+// `sql:"id,pk/insert"`. They are not part of the name.
 func parseTag(tag string, context string) (name string, ignore bool) {
 	ss := strings.Split(tag, "/")
+	name = strings.Split(ss[0], ",")[0]
 	if len(ss) == 1 {
-		return ss[0], ss[0] == "-"
+		return name, name == "-"
 	}
 	context = strings.ToLower(context)
 	for _, v := range ss[1:] {
 		if strings.ToLower(v) == context {
-			return ss[0], true
+			return name, true
+		}
+	}
+	return name, false
+}
+
+// tagOption reports whether the option is given after the column
+// name in tag. E.g., tagOption("id,pk/insert", "pk") is true.
+func tagOption(tag string, option string) bool {
+	ss := strings.Split(strings.Split(tag, "/")[0], ",")
+	for _, v := range ss[1:] {
+		if strings.ToLower(v) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// walkFields calls fn with the tag and index of every tagged,
+// exported field in struct type t, including those of embedded
+// structs. Tags are looked up with the tag key of c. If c has a
+// mapper, untagged exported fields are included, with the mapped
+// column name as their tag.
+//
+// A tag with an empty column name, such as `db:",pk"`, is named by
+// the mapper, or after the lower-cased field name as sqlx does.
+func walkFields(c config, t reflect.Type, fn func(tag string, index []int)) {
+	var recurseFields func(t reflect.Type, base []int)
+	recurseFields = func(t reflect.Type, base []int) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			index := append(append([]int{}, base...), field.Index...)
+			tag, ok := field.Tag.Lookup(c.tagKey())
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if tag != "-" {
+					recurseFields(field.Type, index)
+				}
+				continue
+			}
+			if field.PkgPath != "" {
+				continue // Ignore unexported
+			}
+			if !ok && c.mapper == nil {
+				continue // Ignore untagged
+			}
+			switch {
+			case !ok:
+				tag = c.mapper.column(field.Name)
+			case strings.HasPrefix(tag, ",") || strings.HasPrefix(tag, "/") || tag == "":
+				if c.mapper != nil {
+					tag = c.mapper.column(field.Name) + tag
+				} else {
+					tag = strings.ToLower(field.Name) + tag
+				}
+			}
+			fn(tag, index)
+		}
+	}
+	recurseFields(t, []int{})
+}
+
+// taggedFields returns the column names and field indexes of all
+// tagged fields in struct type t which are not ignored in the given
+// context. The returned slices must not be modified.
+func taggedFields(c config, t reflect.Type, context string) (columns []string, index [][]int) {
+	p := planFor(c, t, context)
+	return p.columns, p.index
+}
+
+// keyFields returns the column names and field indexes of all fields
+// in struct type t tagged with the pk option.
+func keyFields(c config, t reflect.Type) (columns []string, index [][]int) {
+	p := planFor(c, t, "")
+	for i, key := range p.keys {
+		if key {
+			columns = append(columns, p.columns[i])
+			index = append(index, p.index[i])
+		}
+	}
+	return columns, index
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
 		}
 	}
-	return ss[0], false
+	return false
 }
@@ -0,0 +1,137 @@
+package sqlh
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"unicode"
+)
+
+// Params binds the named parameters of a query, written as :name or
+// @name, to the tagged fields of a struct or the keys of a map. It is
+// given as the only argument to Scan, Update, Delete and the like,
+// or to the methods of a DB or Tx.
+//
+//   err := Scan(&dest, db, `select * from X where a = :a and b = @b`,
+//       Bind(map[string]interface{}{"a": 1, "b": 2}))
+//   // = db.Query(`select * from X where a = $1 and b = $2`, 1, 2)
+//
+// Parameters are written as the positional placeholders of the
+// dialect in use. As with reindexing, names within quoted strings are
+// left alone, as are placeholders of the dialect itself, such as @p1
+// for SQLServer, and casts such as ::int.
+type Params struct {
+	v interface{}
+}
+
+// Bind returns the Params for the fields of struct v, or the keys of
+// map v.
+func Bind(v interface{}) Params {
+	return Params{v}
+}
+
+// Value implements driver.Valuer, so that Params given to a database
+// which does not bind them fail with a useful error.
+func (p Params) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("named parameters must be bound by an sqlh function, DB or Tx")
+}
+
+// params returns the Params given as the only argument in args.
+func params(args []interface{}) (Params, bool) {
+	if len(args) != 1 {
+		return Params{}, false
+	}
+	p, ok := args[0].(Params)
+	return p, ok
+}
+
+// bind replaces the named parameters in query with the placeholders
+// of the dialect of c, numbered after the base'th argument, and
+// returns their values.
+func (p Params) bind(c config, query string, base int) (string, []interface{}, error) {
+	lookup, err := p.lookup(c)
+	if err != nil {
+		return "", nil, err
+	}
+	var (
+		args    []interface{}
+		indexes = make(map[string]int)
+		prefix  = []rune(c.dialect.Prefix)
+	)
+	query = substitute(query, func(in []rune, i int) (int, string) {
+		if err != nil || in[i] != ':' && in[i] != '@' || i > 0 && !separator(in[i-1]) {
+			return 0, ""
+		}
+		if hasPrefix(in[i:], prefix) && positional(in[i+len(prefix):]) {
+			return 0, "" // A placeholder of the dialect
+		}
+		end := i + 1
+		for end < len(in) && (in[end] == '_' || unicode.IsLetter(in[end]) || end > i+1 && unicode.IsDigit(in[end])) {
+			end++
+		}
+		if end == i+1 {
+			return 0, "" // Not followed by a name
+		}
+		name := string(in[i+1 : end])
+		if n, ok := indexes[name]; ok && c.dialect.Numbered {
+			return end - i, c.dialect.placeholder(base + n)
+		}
+		v, ok := lookup(name)
+		if !ok {
+			err = fmt.Errorf("no value for parameter %s", string(in[i:end]))
+			return 0, ""
+		}
+		args = append(args, v)
+		indexes[name] = len(args)
+		return end - i, c.dialect.placeholder(base + len(args))
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}
+
+// lookup returns a function giving the value of each named parameter.
+func (p Params) lookup(c config) (func(name string) (interface{}, bool), error) {
+	v := reflect.ValueOf(p.v)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Struct:
+		pl := planFor(c, v.Type(), "")
+		return func(name string) (interface{}, bool) {
+			i, ok := pl.lookup[name]
+			if !ok {
+				return nil, false
+			}
+			return v.FieldByIndex(pl.index[i]).Interface(), true
+		}, nil
+	case v.Kind() == reflect.Map && isMap(v.Type()):
+		return func(name string) (interface{}, bool) {
+			x := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !x.IsValid() {
+				return nil, false
+			}
+			return x.Interface(), true
+		}, nil
+	default:
+		return nil, fmt.Errorf("parameters must be a struct or map, not: %T", p.v)
+	}
+}
+
+// separator reports whether a named parameter may follow rune r, so
+// that e.g. x::int and a[1:n] are left alone.
+func separator(r rune) bool {
+	return r != ':' && r != '@' && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// positional reports whether s starts with an index, ending at a word
+// boundary.
+func positional(s []rune) bool {
+	i := 0
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	return i > 0 && (i == len(s) || separator(s[i]))
+}
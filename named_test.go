@@ -0,0 +1,144 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+	type P struct {
+		ID   int    `sql:"id"`
+		Name string `sql:"name"`
+	}
+	m := map[string]interface{}{"id": 1, "name": "x"}
+
+	tests := []struct {
+		name  string
+		d     Dialect
+		base  int
+		query string
+		v     interface{}
+		exp   string
+		args  []interface{}
+	}{
+		{"struct", Postgres, 0, "id = :id and name = @name", P{1, "x"},
+			"id = $1 and name = $2", []interface{}{1, "x"}},
+		{"pointer to struct", Postgres, 0, "id = :id", &P{ID: 1},
+			"id = $1", []interface{}{1}},
+		{"map", SQLite, 0, "id = :id and name = :name", m,
+			"id = ?1 and name = ?2", []interface{}{1, "x"}},
+		{"repeated name", Postgres, 2, "id = :id or parent = :id", m,
+			"id = $3 or parent = $3", []interface{}{1}},
+		{"repeated name unnumbered", MySQL, 2, "id = :id or parent = :id", m,
+			"id = ? or parent = ?", []interface{}{1, 1}},
+		{"quoted names ignored", Postgres, 0, `name = ':name' and id = :id and "@x" = 1`, m,
+			`name = ':name' and id = $1 and "@x" = 1`, []interface{}{1}},
+		{"casts ignored", Postgres, 0, "id = :id::int and a[1:2] = 1", m,
+			"id = $1::int and a[1:2] = 1", []interface{}{1}},
+		{"sqlserver positional ignored", SQLServer, 1, "id = @p1 and name = @name", m,
+			"id = @p1 and name = @p2", []interface{}{"x"}},
+		{"oracle positional ignored", Oracle, 1, "id = :1 and name = :name", m,
+			"id = :1 and name = :2", []interface{}{"x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := Bind(tt.v).bind(config{dialect: tt.d}, tt.query, tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.exp != query {
+				t.Fatalf("expected: %#v\ngot: %#v", tt.exp, query)
+			}
+			if !reflect.DeepEqual(tt.args, args) {
+				t.Fatalf("expected: %#v\ngot: %#v", tt.args, args)
+			}
+		})
+	}
+
+	t.Run("missing value fails", func(t *testing.T) {
+		_, _, err := Bind(m).bind(config{dialect: Postgres}, "id = :id and x = :missing", 0)
+		if err == nil || err.Error() != "no value for parameter :missing" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("bad value fails", func(t *testing.T) {
+		if _, _, err := Bind(1).bind(config{dialect: Postgres}, "id = :id", 0); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("update where clause", func(t *testing.T) {
+		u, err := update(config{dialect: Postgres}, "T", P{Name: "y"}, "id = :id", Bind(m))
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preUpdate{
+			statement: "UPDATE T SET name = $1 WHERE id = $2",
+			args:      []interface{}{"y", 1},
+		}
+		if !reflect.DeepEqual(exp, *u) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		r := &Recorder{}
+		db, err := Open("sqlite3", ":memory:", Options{Dialect: SQLite, Hooks: []Hook{r}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec(`create table T(id int, name text)`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`insert into T values(:id, :name)`, Bind(P{1, "x"})); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Delete(db.DB, "T", "id = :id", Bind(P{ID: 2})); err != nil {
+			t.Fatal(err)
+		}
+		var dest []P
+		if err := Scan(&dest, db.DB, `select * from T where name = :name`, Bind(m)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([]P{{1, "x"}}, dest) {
+			t.Fatalf("unexpected rows: %#v", dest)
+		}
+		var n int
+		if err := db.QueryRow(`select count(*) from T where id = :id`, Bind(m)).Scan(&n); err != nil || n != 1 {
+			t.Fatalf("unexpected count %d: %v", n, err)
+		}
+
+		r.Reset()
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		for _, row := range []*Row{
+			db.QueryRow(`select count(*) from T where id = :missing`, Bind(m)),
+			tx.QueryRow(`select count(*) from T where id = :missing`, Bind(m)),
+		} {
+			if err := row.Scan(&n); err == nil || err.Error() != "no value for parameter :missing" {
+				t.Fatalf("expected bind error, got: %v", err)
+			}
+		}
+		if events := r.Events(); len(events) != 2 || events[0].Err == nil || events[1].Err == nil {
+			t.Fatalf("expected bind errors to be given to hooks, got: %#v", events)
+		}
+	})
+
+	t.Run("unbound params fail", func(t *testing.T) {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.Exec(`select :id`, Bind(m)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package sqlh
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+// Nullable is a value which is either unset, null, or holds a value
+// of type T. Its zero value is unset.
+//
+// Unset fields are left out of updates, and of inserts where they are
+// unset in every row, while null fields write NULL:
+//
+//   type patch struct {
+//     Name  Nullable[string] `sql:"name"`
+//     Email Nullable[string] `sql:"email"`
+//   }
+//   res, err := Update(db, "users", patch{Email: Null[string]()}, "id = $1", 1)
+//   // = db.Exec(`UPDATE users SET email = $1 WHERE id = $2`, nil, 1)
+//
+// Decoded from JSON, a missing key is unset and a null is null.
+//
+// A nil *Nullable field is treated as any other nil pointer rather
+// than as unset: Update skips it as a zero value, and Insert writes
+// NULL.
+type Nullable[T any] struct {
+	V     T
+	Valid bool // V holds a value, rather than null
+	Set   bool // The value is null, or V is valid
+}
+
+// NewNullable returns a Nullable set to v.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{V: v, Valid: true, Set: true}
+}
+
+// Null returns a Nullable set to null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Set: true}
+}
+
+// IsSet reports whether n is null or holds a value.
+func (n Nullable[T]) IsSet() bool {
+	return n.Set
+}
+
+// Scan implements sql.Scanner. A scanned value is always set.
+func (n *Nullable[T]) Scan(src interface{}) error {
+	var zero T
+	n.V, n.Valid, n.Set = zero, false, true
+	if src == nil {
+		return nil
+	}
+	// sql.Null converts src as rows.Scan would, including
+	// calling the Scan method of T
+	var v sql.Null[T]
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	n.V, n.Valid = v.V, true
+	return nil
+}
+
+// Value implements driver.Valuer. Unset values are written as null.
+func (n Nullable[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+// MarshalJSON implements json.Marshaler. Unset values are written as
+// null.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// If data can't be decoded, n is left unchanged.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NewNullable(v)
+	return nil
+}
+
+// settable is implemented by values which may be left unset, such as
+// Nullable.
+type settable interface {
+	IsSet() bool
+}
+
+var settableType = reflect.TypeOf((*settable)(nil)).Elem()
+
+// unset reports whether v holds a value which has been left unset. A
+// nil pointer, such as a nil *Nullable, is not unset.
+func unset(v reflect.Value) bool {
+	if !v.Type().Implements(settableType) {
+		return false
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return false
+	}
+	return !v.Interface().(settable).IsSet()
+}
@@ -0,0 +1,158 @@
+package sqlh
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNullable(t *testing.T) {
+	type patch struct {
+		ID    int              `sql:"id"`
+		Name  Nullable[string] `sql:"name" json:"name"`
+		Count Nullable[int]    `sql:"count" json:"count"`
+	}
+	c := config{dialect: Postgres}
+
+	t.Run("update skips unset and writes null", func(t *testing.T) {
+		u, err := update(c, "T", patch{Name: Null[string](), Count: NewNullable(0)}, "id = $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := preUpdate{
+			statement: "UPDATE T SET name = $1, count = $2 WHERE id = $3",
+			args:      []interface{}{Null[string](), NewNullable(0), 1},
+		}
+		if !reflect.DeepEqual(exp, *u) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
+		}
+		u, err = updateWith(c, "T", patch{Count: NewNullable(1)}, setMode{all: true}, "id = $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.statement != "UPDATE T SET id = $1, count = $2 WHERE id = $3" {
+			t.Fatalf("unexpected statement: %s", u.statement)
+		}
+	})
+
+	t.Run("insert leaves out columns unset in every row", func(t *testing.T) {
+		i, err := insert(c, "T", []patch{{ID: 1}, {ID: 2, Count: NewNullable(2)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.statement != "insert into T(id, count) values($1, $2), ($3, $4)" {
+			t.Fatalf("unexpected statement: %s", i.statement)
+		}
+	})
+
+	t.Run("nil pointers are not unset", func(t *testing.T) {
+		type ptr struct {
+			A int               `sql:"a"`
+			B *Nullable[string] `sql:"b"`
+		}
+		u, err := update(c, "T", ptr{A: 5}, "a = $1", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.statement != "UPDATE T SET a = $1 WHERE a = $2" {
+			t.Fatalf("unexpected statement: %s", u.statement)
+		}
+		i, err := insert(c, "T", ptr{A: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.statement != "insert into T(a, b) values($1, $2)" {
+			t.Fatalf("unexpected statement: %s", i.statement)
+		}
+		if _, _, err := updateDiff(c, "T", ptr{B: &Nullable[string]{}}, ptr{}, "a = $1", 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var p patch
+		if err := json.Unmarshal([]byte(`{"name": null}`), &p); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(patch{Name: Null[string]()}, p) {
+			t.Fatalf("unexpected patch: %#v", p)
+		}
+		if err := json.Unmarshal([]byte(`{"count": 3}`), &p); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(NewNullable(3), p.Count) {
+			t.Fatalf("unexpected count: %#v", p.Count)
+		}
+		if err := json.Unmarshal([]byte(`{"count": "bad"}`), &p); err == nil {
+			t.Fatal("expected error")
+		}
+		if !reflect.DeepEqual(NewNullable(3), p.Count) {
+			t.Fatalf("expected count to be unchanged by bad input, got: %#v", p.Count)
+		}
+		var bad Nullable[int]
+		if err := bad.UnmarshalJSON([]byte(`"bad"`)); err == nil || bad.Valid || bad.Set {
+			t.Fatalf("expected error and unset value, got: %#v, %v", bad, err)
+		}
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `{"ID":0,"name":null,"count":3}` {
+			t.Fatalf("unexpected json: %s", b)
+		}
+	})
+
+	t.Run("exec and scan", func(t *testing.T) {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec(`create table T(id int, name text default 'default', count int)`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Insert(db, "T", patch{ID: 1, Count: NewNullable(1)}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Update(db, "T", patch{Count: Null[int]()}, "id = ?", 1); err != nil {
+			t.Fatal(err)
+		}
+		var dest patch
+		if err := Scan(&dest, db, `select * from T`); err != nil {
+			t.Fatal(err)
+		}
+		exp := patch{ID: 1, Name: NewNullable("default"), Count: Null[int]()}
+		if !reflect.DeepEqual(exp, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, dest)
+		}
+	})
+
+	t.Run("scan converts driver values", func(t *testing.T) {
+		var b Nullable[bool]
+		var f Nullable[float32]
+		var s Nullable[string]
+		var i Nullable[int8]
+		for _, x := range []struct {
+			dest sql.Scanner
+			src  interface{}
+		}{{&b, int64(1)}, {&f, int64(2)}, {&s, []byte("s")}, {&i, "4"}} {
+			if err := x.dest.Scan(x.src); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if b != NewNullable(true) || f != NewNullable(float32(2)) || s != NewNullable("s") || i != NewNullable(int8(4)) {
+			t.Fatalf("unexpected values: %#v %#v %#v %#v", b, f, s, i)
+		}
+		for _, src := range []interface{}{"300", int64(300), 1.5} {
+			if err := i.Scan(src); err == nil {
+				t.Fatalf("expected error scanning %#v, got: %#v", src, i)
+			}
+		}
+		var n Nullable[int]
+		if err := n.Scan(1.5); err == nil {
+			t.Fatalf("expected error scanning a fraction, got: %#v", n)
+		}
+	})
+}
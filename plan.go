@@ -0,0 +1,73 @@
+package sqlh
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// plan is the mapping between the columns and fields of a struct type
+// in a given context. Plans are cached, so the struct type is only
+// walked once, and must not be modified.
+type plan struct {
+	columns []string       // Column names, in field order
+	index   [][]int        // Field index of each column
+	keys    []bool         // Whether each column has the pk option
+	always  []bool         // Whether each column has the always option
+	lookup  map[string]int // Position of each column in columns
+}
+
+type planKey struct {
+	t       reflect.Type
+	context string
+	tag     string
+	mapper  *Mapper
+}
+
+// plans caches a *plan for each planKey.
+var plans sync.Map
+
+// planFor returns the plan for the tagged fields of struct type t,
+// including those of embedded structs, which are not ignored in the
+// given context. Fields are tagged with the tag key of c, and
+// untagged fields are included if c has a mapper.
+// Where several fields have the same column name, the least nested
+// field is used for lookups.
+func planFor(c config, t reflect.Type, context string) *plan {
+	k := planKey{t, strings.ToLower(context), c.tagKey(), c.mapper}
+	if p, ok := plans.Load(k); ok {
+		return p.(*plan)
+	}
+	p := &plan{lookup: make(map[string]int)}
+	walkFields(c, t, func(tag string, index []int) {
+		name, ignore := parseTag(tag, context)
+		if ignore {
+			return // Explicitly ignored
+		}
+		if j, ok := p.lookup[name]; !ok || len(index) < len(p.index[j]) {
+			p.lookup[name] = len(p.columns)
+		}
+		p.columns = append(p.columns, name)
+		p.index = append(p.index, index)
+		p.keys = append(p.keys, tagOption(tag, "pk"))
+		p.always = append(p.always, tagOption(tag, "always"))
+	})
+	actual, _ := plans.LoadOrStore(k, p)
+	return actual.(*plan)
+}
+
+// columnFields returns the index of the field in struct type t which
+// each column is scanned into.
+func columnFields(c config, t reflect.Type, columns []string) ([][]int, error) {
+	p := planFor(c, t, "select")
+	index := make([][]int, len(columns))
+	for i, col := range columns {
+		j, ok := p.lookup[col]
+		if !ok {
+			return nil, fmt.Errorf("no field for column %s", col)
+		}
+		index[i] = p.index[j]
+	}
+	return index, nil
+}
@@ -0,0 +1,48 @@
+package sqlh
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	type e struct {
+		A int `sql:"a,pk"`
+		B int `sql:"b"`
+	}
+	type row struct {
+		e
+		B string `sql:"b"` // Shadows e.B for lookups
+		C string `sql:"c/select"`
+		d string `sql:"d"` // Unexported, ignored
+		E string
+	}
+	typ := reflect.TypeOf(row{})
+
+	p := planFor(config{}, typ, "select")
+	if !reflect.DeepEqual([]string{"a", "b", "b"}, p.columns) {
+		t.Fatalf("unexpected columns: %#v", p.columns)
+	}
+	if !reflect.DeepEqual([][]int{{0, 0}, {0, 1}, {1}}, p.index) {
+		t.Fatalf("unexpected index: %#v", p.index)
+	}
+	if !reflect.DeepEqual([]bool{true, false, false}, p.keys) {
+		t.Fatalf("unexpected keys: %#v", p.keys)
+	}
+	if p.lookup["b"] != 2 {
+		t.Fatalf("expected b to map to the outer field, got: %d", p.lookup["b"])
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if planFor(config{}, typ, "SELECT") != p {
+				t.Error("expected cached plan")
+			}
+		}()
+	}
+	wg.Wait()
+}
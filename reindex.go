@@ -4,21 +4,57 @@ import (
 	"strconv"
 )
 
-// Update the index of argument placeholders.
-// reindex("where a = $1 and b = $2", 5) -> "where a = $6 and b = $7"
-func reindex(s string, base int) string {
+// Update the index of argument placeholders written in dialect d.
+// reindex("where a = $1 and b = $2", Postgres, 5) -> "where a = $6 and b = $7"
+//
+// Placeholders of unnumbered dialects are left as they are.
+func reindex(s string, d Dialect, base int) string {
+	if !d.Numbered {
+		return s
+	}
+	return rewrite(s, d, func(n int) string {
+		return d.placeholder(base + n)
+	})
+}
+
+// rewrite replaces each numbered placeholder of dialect d, which
+// is not within a single or double-quoted string, with the result
+// of fn given the placeholder's index.
+func rewrite(s string, d Dialect, fn func(n int) string) string {
+	prefix := []rune(d.Prefix)
+	return substitute(s, func(in []rune, i int) (int, string) {
+		if !hasPrefix(in[i:], prefix) {
+			return 0, ""
+		}
+		start := i + len(prefix)
+		end := start
+		for end < len(in) && '0' <= in[end] && in[end] <= '9' {
+			end++
+		}
+		if end == start {
+			return 0, "" // Not followed by an index
+		}
+		n, _ := strconv.Atoi(string(in[start:end]))
+		return end - i, fn(n)
+	})
+}
+
+// substitute calls match at each rune of s which is not within a
+// single or double-quoted string. If match returns n > 0, the n runes
+// from there are replaced with the string it returns.
+func substitute(s string, match func(in []rune, i int) (n int, repl string)) string {
 	var (
 		DEFAULT        = 1
-		PARAMETER      = 2
 		D_QUOTE        = 3
 		D_QUOTE_ESCAPE = 4
 		S_QUOTE        = 5
 		S_QUOTE_ESCAPE = 6
 	)
 	state := DEFAULT
+	in := []rune(s)
 	var result []rune
-	var capture []rune
-	for _, c := range s {
+	for i := 0; i < len(in); i++ {
+		c := in[i]
 		switch {
 		case state == DEFAULT && c == '\'':
 			state = S_QUOTE
@@ -26,22 +62,13 @@ func reindex(s string, base int) string {
 		case state == DEFAULT && c == '"':
 			state = D_QUOTE
 			result = append(result, c)
-		case state == DEFAULT && c == '$':
-			state = PARAMETER
 		case state == DEFAULT:
-			result = append(result, c)
-		case state == PARAMETER && '0' <= c && c <= '9':
-			capture = append(capture, c)
-		case state == PARAMETER:
-			state = DEFAULT
-			param := "$"
-			if len(capture) > 0 {
-				n, _ := strconv.Atoi(string(capture))
-				param += strconv.Itoa(base + n)
+			if n, repl := match(in, i); n > 0 {
+				result = append(result, []rune(repl)...)
+				i += n - 1
+				continue
 			}
-			result = append(result, []rune(param)...)
 			result = append(result, c)
-			capture = make([]rune, 0)
 		case state == D_QUOTE && c == '\\':
 			state = D_QUOTE_ESCAPE
 			result = append(result, c)
@@ -66,13 +93,17 @@ func reindex(s string, base int) string {
 			result = append(result, c)
 		}
 	}
-	if state == PARAMETER {
-		param := "$"
-		if len(capture) > 0 {
-			n, _ := strconv.Atoi(string(capture))
-			param += strconv.Itoa(base + n)
+	return string(result)
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	if len(prefix) == 0 || len(s) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
 		}
-		result = append(result, []rune(param)...)
 	}
-	return string(result)
+	return true
 }
@@ -18,10 +18,11 @@ func TestReindex(t *testing.T) {
 		{`"$2" $2`, 1, `"$2" $3`},
 		{`"$1\"" $2`, 1, `"$1\"" $3`},
 		{`'$1\''`, 1, `'$1\''`},
+		{`$ $a`, 1, `$ $a`},
 	}
 	for i, v := range tests {
 		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
-			result := reindex(v.in, v.base)
+			result := reindex(v.in, Postgres, v.base)
 			if result == v.expect {
 				return
 			}
@@ -29,3 +30,25 @@ func TestReindex(t *testing.T) {
 		})
 	}
 }
+
+func TestReindexDialects(t *testing.T) {
+	type T struct {
+		dialect Dialect
+		in      string
+		expect  string
+	}
+	tests := []T{
+		{MySQL, `a = ? and b = ?`, `a = ? and b = ?`},
+		{SQLite, `a = ?1 and b = ? and c = '?1'`, `a = ?3 and b = ? and c = '?1'`},
+		{SQLServer, `a = @p1 and b = @p10 and c = @x`, `a = @p3 and b = @p12 and c = @x`},
+		{Oracle, `a = :1 and b::text = :2`, `a = :3 and b::text = :4`},
+	}
+	for i, v := range tests {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			result := reindex(v.in, v.dialect, 2)
+			if result != v.expect {
+				t.Fatalf("expect %#v, got %#v", v.expect, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InsertReturning runs an INSERT query like Insert, with a RETURNING
+// clause for the given columns, and scans the returned values back
+// into values. Values must be a pointer to a struct or to a slice of
+// structs, and columns are mapped to fields as in Scan.
+//
+//   type val struct {
+//     Id int `sql:"id/insert"`
+//     B string `sql:"b"`
+//   }
+//   values := []val{{B: "test"}, {B: "test"}}
+//
+//   err := InsertReturning(db, "X", &values, "id")
+//   // = db.Query(`insert into X(b) values($1), ($2) returning id`, "test", "test")
+//   // values[0].Id and values[1].Id are now set
+//
+// If no columns are given, all columns which are not ignored in the
+// select context are returned.
+//
+// Returned rows are assigned to values in order, which relies on the
+// database returning rows in the order they were given, as
+// PostgreSQL and SQLite (3.35 or later) do.
+func InsertReturning(db Querist, table string, values interface{}, columns ...string) error {
+	c := configOf(db)
+	v, i, err := insertReturning(c, table, values, columns)
+	if err != nil {
+		return err
+	}
+	rows, err := db.Query(i.statement, i.args...)
+	if err != nil {
+		return err
+	}
+	return scanReturning(context.Background(), c, v, rows)
+}
+
+// InsertReturningContext is like InsertReturning, but runs the query
+// with a context.
+func InsertReturningContext(ctx context.Context, db QueristContext, table string, values interface{}, columns ...string) error {
+	c := configOf(db)
+	v, i, err := insertReturning(c, table, values, columns)
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, i.statement, i.args...)
+	if err != nil {
+		return err
+	}
+	return scanReturning(ctx, c, v, rows)
+}
+
+func insertReturning(c config, table string, values interface{}, columns []string) (reflect.Value, *preInsert, error) {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Ptr {
+		return v, nil, fmt.Errorf("values is not a pointer type")
+	}
+	v = v.Elem()
+	t := v.Type()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return v, nil, fmt.Errorf("values must be struct or []struct, not: %v", v.Type())
+	}
+
+	i, err := insert(c, table, v.Interface())
+	if err != nil {
+		return v, nil, err
+	}
+
+	if len(columns) < 1 {
+		columns, _ = taggedFields(c, t, "select")
+	}
+	if len(columns) < 1 {
+		return v, nil, fmt.Errorf("no columns to return")
+	}
+
+	i.statement += " returning " + strings.Join(columns, ", ")
+	return v, i, nil
+}
+
+// scanReturning scans each row into the struct v, or into successive
+// elements of the slice of structs v. Rows are closed before
+// returning.
+func scanReturning(ctx context.Context, c config, v reflect.Value, rows *sql.Rows) error {
+	defer rows.Close()
+
+	t := v.Type()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	index, err := columnFields(c, t, columns)
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		target := v
+		if v.Kind() == reflect.Slice {
+			if n >= v.Len() {
+				return fmt.Errorf("more rows returned than values inserted")
+			}
+			target = v.Index(n)
+		} else if n > 0 {
+			return fmt.Errorf("more rows returned than values inserted")
+		}
+		receivers := make([]interface{}, len(columns))
+		for i := range columns {
+			receivers[i] = target.FieldByIndex(index[i]).Addr().Interface()
+		}
+		if err := rows.Scan(receivers...); err != nil {
+			return err
+		}
+		n++
+	}
+	return rows.Err()
+}
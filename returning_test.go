@@ -0,0 +1,90 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestInsertReturning(t *testing.T) {
+	type e struct {
+		ID int `sql:"id/insert"`
+	}
+	type row struct {
+		e
+		A string `sql:"a"`
+		B string `sql:"b/select"`
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("statement", func(t *testing.T) {
+		rows := []row{{A: "x"}, {A: "y"}}
+		_, i, err := insertReturning(config{dialect: Postgres}, "T", &rows, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into T(a, b) values($1, $2), ($3, $4) returning id, a`
+		if statement != i.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, i.statement)
+		}
+		_, i, err = insertReturning(config{dialect: Postgres}, "T", &rows[0], []string{"id"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement = `insert into T(a, b) values($1, $2) returning id`
+		if statement != i.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, i.statement)
+		}
+	})
+
+	t.Run("values must be a pointer", func(t *testing.T) {
+		if _, _, err := insertReturning(config{dialect: Postgres}, "T", row{}, nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("scan returned rows into slice", func(t *testing.T) {
+		values := []row{{A: "x"}, {A: "y"}}
+		rows, err := db.Query(`select 1 as id union all select 2`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := scanReturning(context.Background(), config{}, reflect.ValueOf(&values).Elem(), rows); err != nil {
+			t.Fatal(err)
+		}
+		expect := []row{{e{1}, "x", ""}, {e{2}, "y", ""}}
+		if !reflect.DeepEqual(expect, values) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, values)
+		}
+	})
+
+	t.Run("scan returned row into struct", func(t *testing.T) {
+		value := row{A: "x"}
+		rows, err := db.Query(`select 1 as id, 'z' as a`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := scanReturning(context.Background(), config{}, reflect.ValueOf(&value).Elem(), rows); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(row{e{1}, "z", ""}, value) {
+			t.Fatalf("got: %#v", value)
+		}
+	})
+
+	t.Run("too many rows returned fails", func(t *testing.T) {
+		value := row{A: "x"}
+		rows, err := db.Query(`select 1 as id union all select 2`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := scanReturning(context.Background(), config{}, reflect.ValueOf(&value).Elem(), rows); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
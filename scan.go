@@ -1,6 +1,7 @@
 package sqlh
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -13,6 +14,12 @@ type Querist interface {
 	Query(query string, args ...interface{}) (*sql.Rows, error)
 }
 
+// QueristContext is the minimal set of function needed from an
+// *sql.DB to run a query with a context.
+type QueristContext interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
 // Scan is a short-hand for scanning a set of rows into a slice,
 // or a single row into a scalar. Example:
 //
@@ -28,6 +35,16 @@ type Querist interface {
 // If only a single column is returned by the query, the destination
 // can be a base type (e.g., a string).
 //
+// The destination may also be an array, which is filled with as many
+// rows as it holds, and slices or arrays may hold pointers to structs.
+// A pointer to a struct pointer is allocated when there is a row, and
+// set to nil otherwise, rather than returning sql.ErrNoRows.
+//
+//   var dest7 []*struct{A, B string}
+//   _ = Scan(&dest7, db, `select a, b from C`)
+//   var dest8 *struct{A, B string}
+//   _ = Scan(&dest8, db, `select a, b from C limit 1`)
+//
 // If some fields in the destination struct are slices; then the
 // results will be grouped by unique tuples of all non-slice fields,
 // the slice fields will contain an aggregate of values from the
@@ -36,13 +53,66 @@ type Querist interface {
 //   var dest struct{A string, B []string}
 //   _ = Scan(&dest, db, `select a, b from C`)
 //   // => [{"red", ["one", "two"]}, {"blue", ["three", "four", "five"]}]
+//
+// Slices of structs are filled in the same way, from the columns
+// prefixed with the slice field's name and "_", so that a join can
+// fill in a row along with its children. Children are grouped by
+// their own non-slice fields, and may be nested further.
+//
+//   type order struct{ID int `sql:"id"`; Total int `sql:"total"`}
+//   var dest []struct{
+//     Name string `sql:"name"`
+//     Orders []order `sql:"order"`
+//   }
+//   _ = Scan(&dest, db, `select c.name, o.id as order_id, o.total as order_total
+//       from C c left join O o on o.c = c.id`)
+//
+// Rows of any shape can be scanned into maps from column name to
+// value, or into a Table.
+//
+//   var dest5 []map[string]interface{}
+//   _ = Scan(&dest5, db, `select * from C`)
+//   var dest6 Table
+//   _ = Scan(&dest6, db, `select * from C`)
+//
+// Slice arguments are expanded into a list of placeholders, for use
+// in IN clauses. Empty slices are an error.
+//
+//   _ = Scan(&dest, db, `select * from C where a in ($1)`, []string{"red", "blue"})
+//   // = db.Query(`select * from C where a in ($1, $2)`, "red", "blue")
 func Scan(dest interface{}, db Querist, query string, args ...interface{}) error {
-	atleastOneRow := false
-
+	c := configOf(db)
+	query, args, err := prepare(c, query, args, 0)
+	if err != nil {
+		return err
+	}
 	rows, err := db.Query(query, args...)
 	if err != nil {
 		return err
 	}
+	return scan(context.Background(), c, dest, rows)
+}
+
+// ScanContext is like Scan, but runs the query with a context. If the
+// context is cancelled, the query is cancelled and scanning stops
+// with the context's error.
+func ScanContext(ctx context.Context, dest interface{}, db QueristContext, query string, args ...interface{}) error {
+	c := configOf(db)
+	query, args, err := prepare(c, query, args, 0)
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return scan(ctx, c, dest, rows)
+}
+
+// scan reads rows into dest, as described for Scan. Rows are closed
+// before returning.
+func scan(ctx context.Context, c config, dest interface{}, rows *sql.Rows) error {
+	atleastOneRow := false
 	defer rows.Close()
 
 	// Ensure dest is a pointer
@@ -52,10 +122,40 @@ func Scan(dest interface{}, db Querist, query string, args ...interface{}) error
 	}
 	v = v.Elem()
 
-	// Get element (row) type
+	// A pointer to a struct is allocated when a row is scanned
+	// into it, and left nil if there are no rows.
+	if v.Kind() == reflect.Ptr && isRow(v.Type().Elem()) {
+		p := reflect.New(v.Type().Elem())
+		switch err := scan(ctx, c, p.Interface(), rows); err {
+		case nil:
+			v.Set(p)
+			return nil
+		case sql.ErrNoRows:
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		default:
+			return err
+		}
+	}
+
+	// Get element (row) type. The elements of a slice or array
+	// may also be pointers to a struct row type.
 	t := v.Type()
-	if v.Kind() == reflect.Slice {
+	elemPtr := false
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
 		t = t.Elem()
+		if t.Kind() == reflect.Ptr && isRow(t.Elem()) {
+			t = t.Elem()
+			elemPtr = true
+		}
+	}
+
+	// elem converts a row into an element of the destination
+	elem := func(row reflect.Value) reflect.Value {
+		if elemPtr {
+			return row.Addr()
+		}
+		return row
 	}
 
 	columns, err := rows.Columns()
@@ -63,61 +163,73 @@ func Scan(dest interface{}, db Querist, query string, args ...interface{}) error
 		return err
 	}
 
+	switch {
+	case v.Type() == tableType:
+		return scanTable(ctx, v.Addr().Interface().(*Table), rows, columns)
+	case t.Kind() == reflect.Map:
+		return scanMaps(ctx, v, rows, columns)
+	}
+
+	// Find the field each column is scanned into, if the
+	// destination row type is a struct.
+	var g *group
+	if isRow(t) {
+		if g, err = newGroup(c, t, columns); err != nil {
+			return err
+		}
+		// Rows are only grouped into a slice, so a scalar
+		// destination takes the first group of a slice.
+		if v.Kind() == reflect.Struct && g.grouped() {
+			all := reflect.New(reflect.SliceOf(t))
+			if err := scan(ctx, c, all.Interface(), rows); err != nil {
+				return err
+			}
+			if all.Elem().Len() < 1 {
+				return sql.ErrNoRows
+			}
+			v.Set(all.Elem().Index(0))
+			return nil
+		}
+		// Rows are also grouped into the elements already in
+		// the destination.
+		if v.Kind() == reflect.Slice && g.grouped() {
+			g.seed(v, nil)
+		}
+	} else if len(columns) != 1 {
+		return fmt.Errorf("can't scan %d columns into %s", len(columns), t)
+	}
+
+	n := 0 // Rows scanned into an array
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Once an array is full, further rows are ignored
+		if v.Kind() == reflect.Array && n == v.Len() {
+			break
+		}
+
 		// Choose target for this row. If the destination is a
-		// slice, the target is a new value of the row
+		// slice or array, the target is a new value of the row
 		// type. If the destination is a scalar, the target is
 		// the desination itself.
 		target := v
-		if v.Kind() == reflect.Slice {
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
 			target = reflect.New(t).Elem()
 		}
 
 		// Build an array of receiver pointers which are
 		// passed to rows.Scan. If the destination row type is
 		// a struct, receivers will contain pointers to
-		// appropriate fields within the struct. If it is a
-		// base type, receivers will contain a pointer to the
+		// appropriate fields within the struct, or to
+		// temporary values for slice fields. If it is a base
+		// type, receivers will contain a pointer to the
 		// destination itself.
 		receivers := make([]interface{}, len(columns))
-		aggregates := make([]string, 0) // Fields which are slices to aggregate into
-		aggrVals := make([]reflect.Value, 0)
-		keys := make([]string, 0) // Fields to use as grouping key
-		if t.Kind() == reflect.Struct {
-			for i, col := range columns {
-				structField, ok := t.FieldByNameFunc(func(s string) bool {
-					if field, ok := t.FieldByName(s); ok {
-						tag, ok := field.Tag.Lookup("sql")
-						if !ok {
-							return false
-						}
-						name, ignore := parseTag(tag, "select")
-						if name == col {
-							return !ignore
-						}
-						return false
-					}
-					return false
-				})
-				if !ok {
-					return fmt.Errorf("no field for column %s", col)
-				}
-				// If the field is a slice; scan into
-				// a temporary value of the element
-				// type, for later aggregation.
-				field := target.FieldByName(structField.Name)
-				if field.Kind() == reflect.Slice {
-					field = reflect.New(reflect.PtrTo(field.Type().Elem()))
-					aggregates = append(aggregates, structField.Name)
-					aggrVals = append(aggrVals, field)
-				} else {
-					field = field.Addr()
-					keys = append(keys, structField.Name)
-				}
-				receivers[i] = field.Interface()
-			}
-		} else if len(columns) != 1 {
-			return fmt.Errorf("can't scan %d columns into %s", len(columns), t)
+		var r *row
+		if g != nil {
+			r = g.newRow(target, receivers, true)
 		} else {
 			receivers[0] = target.Addr().Interface()
 		}
@@ -127,46 +239,19 @@ func Scan(dest interface{}, db Querist, query string, args ...interface{}) error
 			return err
 		}
 
-		// Try to find an existing row in the result set, to
-		// which we can aggregate the current row.
-		if v.Kind() == reflect.Slice {
-			aggregated := false
-		rows:
-			for i := 0; i < v.Len() && len(aggregates) > 0; i++ {
-				// Check that all key fields match current row
-				for _, name := range keys {
-					x := v.Index(i).FieldByName(name).Interface()
-					y := target.FieldByName(name).Interface()
-					if !reflect.DeepEqual(x, y) {
-						continue rows // Keys don't match on this row, so skip
-					}
-				}
-				// Keys have all matched, so add to
-				// this row instead of appending a new
-				// row
-				for j, name := range aggregates {
-					existing := v.Index(i).FieldByName(name)
-					// If result wasn't NULL; add to aggregate
-					new := aggrVals[j].Elem()
-					if !new.IsNil() {
-						existing.Set(reflect.Append(existing, new.Elem()))
-					}
-				}
-				aggregated = true
-			}
-			// If we couldn't aggregate current row with
-			// an existing, append current row to result
-			// set.
-			if !aggregated {
-				for i, name := range aggregates {
-					field := target.FieldByName(name)
-					new := aggrVals[i].Elem()
-					if !new.IsNil() {
-						field.Set(reflect.Append(field, new.Elem()))
-					}
-				}
-				v.Set(reflect.Append(v, target))
+		if v.Kind() == reflect.Slice && g != nil {
+			// Aggregate the current row into an
+			// existing row of the result set if
+			// possible, otherwise append it.
+			g.merge(v, r, g.grouped(), elemPtr, nil)
+		} else if v.Kind() == reflect.Slice {
+			v.Set(reflect.Append(v, elem(target)))
+		} else if v.Kind() == reflect.Array {
+			if g != nil {
+				g.apply(target, r, elemKey{nil, n})
 			}
+			v.Index(n).Set(elem(target))
+			n++
 		} else {
 			// If destination was a scalar, we only need the first row
 			atleastOneRow = true
@@ -179,7 +264,7 @@ func Scan(dest interface{}, db Querist, query string, args ...interface{}) error
 	}
 
 	// If destination was scalar, ensure we got atleast one row
-	if v.Kind() != reflect.Slice && !atleastOneRow {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array && !atleastOneRow {
 		return sql.ErrNoRows
 	}
 
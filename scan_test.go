@@ -1,6 +1,7 @@
 package sqlh
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
 	"regexp"
@@ -156,6 +157,87 @@ func TestScan(t *testing.T) {
 			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
 		}
 	})
+
+	t.Run("dest is slice of struct pointers", func(t *testing.T) {
+		var dest []*a
+		if err := Scan(&dest, db, `select * from A`); err != nil {
+			t.Fatal(err)
+		}
+		if len(dest) != len(expect) {
+			t.Fatalf("expected %d rows, got: %d", len(expect), len(dest))
+		}
+		for i := range expect {
+			if !reflect.DeepEqual(expect[i], *dest[i]) {
+				t.Fatalf("row %d expected: %#v\ngot: %#v", i, expect[i], *dest[i])
+			}
+		}
+	})
+
+	t.Run("dest is struct pointer", func(t *testing.T) {
+		var dest *a
+		if err := Scan(&dest, db, `select * from A limit 1`); err != nil {
+			t.Fatal(err)
+		}
+		if dest == nil || !reflect.DeepEqual(expect[0], *dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect[0], dest)
+		}
+		if err := Scan(&dest, db, `select * from A where 0`); err != nil {
+			t.Fatal(err)
+		}
+		if dest != nil {
+			t.Fatalf("expected nil, got: %#v", dest)
+		}
+	})
+
+	t.Run("dest is array", func(t *testing.T) {
+		var dest [2]a
+		if err := Scan(&dest, db, `select * from A`); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([2]a{expect[0], expect[1]}, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect[:2], dest)
+		}
+		var dest2 [4]*a
+		if err := Scan(&dest2, db, `select * from A`); err != nil {
+			t.Fatal(err)
+		}
+		if dest2[3] != nil || !reflect.DeepEqual(expect[2], *dest2[2]) {
+			t.Fatalf("unexpected rows: %#v", dest2)
+		}
+	})
+
+	t.Run("aggregate into slice of struct pointers", func(t *testing.T) {
+		type d struct {
+			A     string   `sql:"a"`
+			Group []string `sql:"gr"`
+		}
+		var dest []*d
+		if err := Scan(&dest, db, `select a, gr from A left join B on a = id`); err != nil {
+			t.Fatal(err)
+		}
+		if len(dest) != 3 || !reflect.DeepEqual([]string{"group1", "group2"}, dest[1].Group) {
+			t.Fatalf("unexpected rows: %#v", dest)
+		}
+	})
+
+	t.Run("scan with context", func(t *testing.T) {
+		var dest []a
+		if err := ScanContext(context.Background(), &dest, db, `select * from A`); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+
+	t.Run("scan with cancelled context fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var dest []a
+		if err := ScanContext(ctx, &dest, db, `select * from A`); err != context.Canceled {
+			t.Fatalf("expected %v, got: %v", context.Canceled, err)
+		}
+	})
 }
 
 func BenchmarkScan(b *testing.B) {
@@ -183,3 +265,34 @@ func BenchmarkScan(b *testing.B) {
 		panic("incorrect number of rows returned")
 	}
 }
+
+func BenchmarkScan100k(b *testing.B) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	_panic(err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`create table A(a text, b int, c text)`)
+	_panic(err)
+	type row struct {
+		A string `sql:"a"`
+		B int    `sql:"b"`
+		C string `sql:"c"`
+	}
+	rows := make([]row, 100000)
+	for i := range rows {
+		rows[i] = row{"testing", i, "testing"}
+	}
+	_, err = InsertBatchTx(context.Background(), db, "A", rows, 0)
+	_panic(err)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var dest []row
+		err = Scan(&dest, db, `select * from A`)
+		_panic(err)
+		if len(dest) != len(rows) {
+			panic("incorrect number of rows returned")
+		}
+	}
+}
@@ -12,7 +12,24 @@
 // And finally, it can be given a column mapping which is ignored in
 // specific contexts with `sql:"col_name/insert/update"`. The column name
 // comes before a sequence of "/" + context, where context is a query
-// or statement type such as select, update, or insert.
+// or statement type such as select, update, or insert. Upsert inserts
+// columns ignored in the upsert context, but keeps their existing value
+// on conflict.
+// 
+// Options may follow the column name, separated by commas. The pk
+// option marks a column as part of the table's key, as in
+// `sql:"id,pk/insert"`. Get, Exists, UpdateByKey and DeleteByKey use
+// the key columns to find a row, so no where clause is needed. A
+// composite key is formed by marking several columns.
+// 
+// The tag key can be changed by setting DefaultTag, e.g. to "db" to use
+// structs already tagged for sqlx. Options after the column name, such
+// as `db:"name,omitempty"`, are accepted, and unknown ones are ignored.
+// 
+// Fields without a `sql` tag are ignored, unless DefaultMapper is set to
+// map their names to columns, e.g. SnakeCase maps UserID to user_id.
+// Custom mappings can be made with NewMapper. Tags always take
+// precedence over the mapper.
 // 
 // Below we show an example of typical use, given the following schema.
 // 
@@ -33,7 +50,7 @@
 //   res, err := Insert(db, "T", row{Name:"a"})
 //   // INSERT INTO T(name, data) VALUES('a', NULL);
 // 
-//    _, err := Update(db, "T", row{Data:"updated"}, "id = ?", res.LastInsertId)
+//    _, err := Update(db, "T", row{Data:"updated"}, "id = $1", res.LastInsertId)
 //    // UPDATE T SET data = 'updated' WHERE id = 1;
 // 
 //    var dest row
@@ -42,8 +59,48 @@
 // In the example, we avoid inserting the primary key, as the DBMS can
 // handle that. In the update, note that only the data column is
 // set. This is because struct fields with a zero-value are ignored in an
-// update (the same rule does not apply to insert).
+// update (the same rule does not apply to insert). Fields tagged with
+// the always option, as in `sql:"active,always"`, are set even when
+// zero, as are the columns named in UpdateFields, or every column with
+// UpdateAll. UpdateDiff instead sets exactly the columns which differ
+// between an old and a new value.
+// 
+// Bind parameters in generated statements are written in the $1, $2
+// style used by PostgreSQL. Other databases are supported by setting
+// DefaultDialect, e.g. to MySQL for ?, or SQLServer for @p1, @p2. Where
+// clauses given to Update must use the same style.
+// 
+// The package level settings, DefaultDialect, DefaultTag and
+// DefaultMapper, can instead be given per database by wrapping an
+// *sql.DB in a DB with NewDB. A DB, and any Tx begun from it, can be
+// passed to every function in place of an *sql.DB.
+// 
+// A DB can also be given Hooks, which are called before and after every
+// statement it runs with the statement, its arguments, duration, rows
+// affected and error. They can be used for logging, tracing or metrics,
+// and a Recorder hook keeps every statement for inspection in tests.
+// 
+// Statements can be built without running them with BuildInsert,
+// BuildUpsert and BuildUpdate, which return the SQL and its arguments as
+// a Statement, e.g. to log them or run them elsewhere.
+// 
+// Insert and Update also accept a map[string]interface{} in place of a
+// struct, with columns in sorted key order. As map keys are written into
+// the statement, maps from untrusted input should be checked against
+// the permitted columns with AllowColumns.
+// 
+// To tell a field which should be left alone from one which should be
+// set to NULL, use Nullable[T]. An unset Nullable is skipped by Update,
+// a null one writes NULL, and both can be decoded from JSON.
+// 
+// Queries and where clauses may use named parameters, written as :name
+// or @name, bound to the fields of a struct or keys of a map given with
+// Bind. They are rewritten into the placeholders of the dialect in use.
 // 
+// Slice arguments to Scan, Update and Delete are expanded into lists of
+// placeholders for IN clauses, so `id in ($1)` with []int{1, 2} becomes
+// `id in ($1, $2)`, and later placeholders are renumbered to match.
+// Empty slices are an error.
 package sqlh
 
 // Code generated by ./readme.awk: DO NOT EDIT.
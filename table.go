@@ -0,0 +1,104 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Table holds the result of a query whose shape is not known in
+// advance. It is filled in by passing a *Table to Scan.
+type Table struct {
+	Columns []string        // Column names
+	Types   []string        // Database type names, e.g. "VARCHAR"
+	Rows    [][]interface{} // Values of each row, in column order
+}
+
+var tableType = reflect.TypeOf(Table{})
+
+// scanTable reads all rows into dest.
+func scanTable(ctx context.Context, dest *Table, rows *sql.Rows, columns []string) error {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	dest.Columns = columns
+	dest.Types = make([]string, len(types))
+	for i, t := range types {
+		dest.Types[i] = t.DatabaseTypeName()
+	}
+	dest.Rows = make([][]interface{}, 0)
+
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		values := make([]interface{}, len(columns))
+		receivers := make([]interface{}, len(columns))
+		for i := range values {
+			receivers[i] = &values[i]
+		}
+		if err := rows.Scan(receivers...); err != nil {
+			return err
+		}
+		dest.Rows = append(dest.Rows, values)
+	}
+	return rows.Err()
+}
+
+// scanMaps reads rows into v, which is either a map from column name
+// to value, or a slice or array of them. A map only receives the
+// first row, and an array as many rows as it holds.
+func scanMaps(ctx context.Context, v reflect.Value, rows *sql.Rows, columns []string) error {
+	t := v.Type()
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Key().Kind() != reflect.String {
+		return fmt.Errorf("can't scan into map with %s keys", t.Key())
+	}
+
+	n := 0 // Rows scanned into an array
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Once an array is full, further rows are ignored
+		if v.Kind() == reflect.Array && n == v.Len() {
+			break
+		}
+		receivers := make([]interface{}, len(columns))
+		for i := range receivers {
+			receivers[i] = reflect.New(t.Elem()).Interface()
+		}
+		if err := rows.Scan(receivers...); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(t, len(columns))
+		for i, col := range columns {
+			key := reflect.ValueOf(col).Convert(t.Key())
+			m.SetMapIndex(key, reflect.ValueOf(receivers[i]).Elem())
+		}
+		switch v.Kind() {
+		case reflect.Slice:
+			v.Set(reflect.Append(v, m))
+		case reflect.Array:
+			v.Index(n).Set(m)
+			n++
+		default:
+			v.Set(m)
+			return rows.Err()
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// If destination was a single map, ensure we got atleast one row
+	if v.Kind() == reflect.Map {
+		return sql.ErrNoRows
+	}
+	return nil
+}
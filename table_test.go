@@ -0,0 +1,91 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanDynamic(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	_panic(err)
+	defer db.Close()
+	for i, v := range strings.Split(schema, ";") {
+		if _, err := db.Exec(v); err != nil {
+			t.Fatalf("exec schema %d: %s:\n%s", i, err, v)
+		}
+	}
+
+	t.Run("scan into slice of maps", func(t *testing.T) {
+		var dest []map[string]interface{}
+		if err := Scan(&dest, db, `select a, b from A`); err != nil {
+			t.Fatal(err)
+		}
+		if len(dest) != len(expect) {
+			t.Fatalf("expected %d rows, got: %d", len(expect), len(dest))
+		}
+		exp := map[string]interface{}{"a": "one", "b": int64(1)}
+		if !reflect.DeepEqual(exp, dest[0]) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, dest[0])
+		}
+	})
+
+	t.Run("scan into map", func(t *testing.T) {
+		var dest map[string]string
+		if err := Scan(&dest, db, `select a, c from A limit 1`); err != nil {
+			t.Fatal(err)
+		}
+		exp := map[string]string{"a": "one", "c": "red"}
+		if !reflect.DeepEqual(exp, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, dest)
+		}
+		if err := Scan(&dest, db, `select a from A where 0`); err != sql.ErrNoRows {
+			t.Fatalf("expected %v, got: %v", sql.ErrNoRows, err)
+		}
+	})
+
+	t.Run("scan into array of maps", func(t *testing.T) {
+		var dest [2]map[string]string
+		if err := Scan(&dest, db, `select a from A`); err != nil {
+			t.Fatal(err)
+		}
+		exp := [2]map[string]string{{"a": "one"}, {"a": "two"}}
+		if !reflect.DeepEqual(exp, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, dest)
+		}
+		var dest2 [4]map[string]string
+		if err := Scan(&dest2, db, `select a from A`); err != nil {
+			t.Fatal(err)
+		}
+		if dest2[2]["a"] != "three" || dest2[3] != nil {
+			t.Fatalf("unexpected rows: %#v", dest2)
+		}
+	})
+
+	t.Run("map keys must be strings", func(t *testing.T) {
+		var dest map[int]string
+		if err := Scan(&dest, db, `select a from A`); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("scan into table", func(t *testing.T) {
+		var dest Table
+		if err := Scan(&dest, db, `select a, b from A`); err != nil {
+			t.Fatal(err)
+		}
+		exp := Table{
+			Columns: []string{"a", "b"},
+			Types:   []string{"text", "int"},
+			Rows: [][]interface{}{
+				{"one", int64(1)},
+				{"two", int64(2)},
+				{"three", int64(3)},
+			},
+		}
+		if !reflect.DeepEqual(exp, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, dest)
+		}
+	})
+}
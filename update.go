@@ -1,6 +1,7 @@
 package sqlh
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -12,6 +13,12 @@ type Executor interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
 }
 
+// ExecutorContext is an interface with just *sql.DB.ExecContext
+// behaviour.
+type ExecutorContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
 // Update runs an SQL UPDATE query. It takes a database, target table,
 // update value, and where clause with arguments.
 //
@@ -21,7 +28,17 @@ type Executor interface {
 //   }
 //   res, err := Update(db, "X", row{Name: "updated"}, "id = $", 1)
 //
-// Zero-values in the value struct are ignored.
+// Zero-values in the value struct are ignored, unless the field is
+// tagged with the always option, as in `sql:"active,always"`. See
+// UpdateFields and UpdateAll to write other zero values.
+//
+// The value may also be a map[string]interface{}, in which case every
+// key is set, in sorted order, including those with zero or nil
+// values. As with Insert, untrusted maps should first be checked
+// with AllowColumns.
+//
+// Placeholders are written in DefaultDialect, and the where clause
+// must use the same dialect.
 //
 // Note that argument placeholders in the WHERE clause are
 // reindexed. I.e., if you pass in a struct with 3 fields, then a
@@ -31,55 +48,148 @@ type Executor interface {
 // The rewriter is smart enough to ignore $N within single or
 // double-quoted strings, and to handle backslash escapes quotes
 // within strings. E.g., `where cost = "$200"` will not be changed.
+//
+// The where clause may instead use named parameters, given with Bind,
+// and slice arguments are expanded into lists as described for Scan.
 func Update(db Executor, table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
-	u, err := update(table, value, where, args...)
+	u, err := update(configOf(db), table, value, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return db.Exec(u.statement, u.args...)
+}
+
+// UpdateContext is like Update, but executes the statement with a
+// context.
+func UpdateContext(ctx context.Context, db ExecutorContext, table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
+	u, err := update(configOf(db), table, value, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, u.statement, u.args...)
+}
+
+// UpdateFields is like Update, but the named columns are written
+// even if their field holds a zero value.
+//
+//   res, err := UpdateFields(db, "X", row{Count: 0}, []string{"count"}, "id = $1", 1)
+//   // = db.Exec(`UPDATE X SET count = $1 WHERE id = $2`, 0, 1)
+func UpdateFields(db Executor, table string, value interface{}, fields []string, where string, args ...interface{}) (sql.Result, error) {
+	u, err := updateWith(configOf(db), table, value, setMode{fields: fields}, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return db.Exec(u.statement, u.args...)
+}
+
+// UpdateFieldsContext is like UpdateFields, but executes the
+// statement with a context.
+func UpdateFieldsContext(ctx context.Context, db ExecutorContext, table string, value interface{}, fields []string, where string, args ...interface{}) (sql.Result, error) {
+	u, err := updateWith(configOf(db), table, value, setMode{fields: fields}, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, u.statement, u.args...)
+}
+
+// UpdateAll is like Update, but every column not ignored in the
+// update context is written, including zero values.
+func UpdateAll(db Executor, table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
+	u, err := updateWith(configOf(db), table, value, setMode{all: true}, where, args...)
 	if err != nil {
 		return nil, err
 	}
 	return db.Exec(u.statement, u.args...)
 }
 
+// UpdateAllContext is like UpdateAll, but executes the statement with
+// a context.
+func UpdateAllContext(ctx context.Context, db ExecutorContext, table string, value interface{}, where string, args ...interface{}) (sql.Result, error) {
+	u, err := updateWith(configOf(db), table, value, setMode{all: true}, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, u.statement, u.args...)
+}
+
 type preUpdate struct {
 	statement string
 	args      []interface{}
 }
 
-func update(table string, value interface{}, where string, args ...interface{}) (*preUpdate, error) {
+func update(c config, table string, value interface{}, where string, args ...interface{}) (*preUpdate, error) {
+	return updateWith(c, table, value, setMode{}, where, args...)
+}
+
+// updateWith is like update, with the fields written chosen by m.
+func updateWith(c config, table string, value interface{}, m setMode, where string, args ...interface{}) (*preUpdate, error) {
 	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Map && isMap(v.Type()) {
+		set, vals := mapSet(c, v)
+		return buildUpdate(c, table, set, vals, where, args...)
+	}
 	if v.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("update was not a struct: %v", v.Type())
 	}
-	var set []string
-	var vals []interface{}
-
-	var recurseFields func(t reflect.Type, index []int)
-	recurseFields = func(t reflect.Type, index []int) {
-		valIndex := 1
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			if field.Anonymous {
-				recurseFields(field.Type, append(index, field.Index...))
-				continue
-			}
-			tag, ok := field.Tag.Lookup("sql")
-			if !ok {
-				continue // Ignore untagged
-			}
-			name, ignore := parseTag(tag, "update")
-			if ignore {
-				continue // Explicitly ignored
-			}
-			value := v.FieldByIndex(append(index, field.Index...))
-			if value.IsZero() {
-				continue // Ignore zero-value
-			}
-			set = append(set, name+fmt.Sprintf(" = $%d", valIndex))
-			valIndex = valIndex + 1
-			vals = append(vals, value.Interface())
+	set, vals, err := updateSet(c, v, m)
+	if err != nil {
+		return nil, err
+	}
+	return buildUpdate(c, table, set, vals, where, args...)
+}
+
+// setMode chooses which fields updateSet writes. By default only
+// non-zero fields, and those tagged with the always option, are
+// written. Unset fields, such as an unset Nullable, are never written.
+type setMode struct {
+	skipKeys bool     // Leave out fields tagged with the pk option
+	all      bool     // Write zero values too
+	fields   []string // Columns written even if zero
+}
+
+// updateSet returns the assignments for the SET clause of an update,
+// and their arguments, from the fields of struct v chosen by m.
+func updateSet(c config, v reflect.Value, m setMode) (set []string, vals []interface{}, err error) {
+	p := planFor(c, v.Type(), "update")
+	force := make([]bool, len(p.columns))
+	for _, name := range m.fields {
+		i, ok := p.lookup[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("no field for column %s", name)
 		}
+		force[i] = true
 	}
-	recurseFields(v.Type(), []int{})
+	for i, name := range p.columns {
+		if m.skipKeys && p.keys[i] {
+			continue
+		}
+		value := v.FieldByIndex(p.index[i])
+		if unset(value) {
+			continue // Ignore unset Nullable
+		}
+		if value.IsZero() && !m.all && !force[i] && !p.always[i] {
+			continue // Ignore zero-value
+		}
+		set = append(set, name+" = "+c.dialect.placeholder(len(vals)+1))
+		vals = append(vals, value.Interface())
+	}
+	return set, vals, nil
+}
 
+// mapSet returns the assignments for the SET clause of an update,
+// and their arguments, from every key of map v.
+func mapSet(c config, v reflect.Value) (set []string, vals []interface{}) {
+	columns := mapColumns(v)
+	vals, _ = mapValues(v, columns)
+	for i, name := range columns {
+		set = append(set, name+" = "+c.dialect.placeholder(i+1))
+	}
+	return set, vals
+}
+
+// buildUpdate builds an update statement from SET assignments and a
+// where clause, along with their arguments.
+func buildUpdate(c config, table string, set []string, vals []interface{}, where string, args ...interface{}) (*preUpdate, error) {
 	if len(set) < 1 {
 		return nil, fmt.Errorf("no fields to update")
 	}
@@ -87,8 +197,12 @@ func update(table string, value interface{}, where string, args ...interface{})
 	// Shift index argument placeholders in where query. The
 	// arguments for the where clause are supplied after the SET
 	// arguments. This is to work around sqlite3's lack of support
-	// for index based arguments.
-	where = reindex(where, len(vals))
+	// for index based arguments. Named parameters and expanded
+	// slices are numbered after the SET arguments in the same way.
+	where, args, err := prepare(c, where, args, len(vals))
+	if err != nil {
+		return nil, err
+	}
 
 	setStmt := strings.Join(set, ", ")
 	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, setStmt, where)
@@ -1,6 +1,7 @@
 package sqlh
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
 	"regexp"
@@ -56,14 +57,14 @@ insert into Z values(null, null, null, null, null, null),('test', 'test', 1, 1,
 	})
 
 	t.Run(`sql:"-" tag ignored`, func(t *testing.T) {
-		_, err := update("Z", up{Z: "test"}, "skdjfsd")
+		_, err := update(config{dialect: Postgres}, "Z", up{Z: "test"}, "skdjfsd")
 		if !match.MatchString(err.Error()) {
 			t.Fatalf("expected error matching %v, got: %s", match, err)
 		}
 	})
 
 	t.Run("unexported field ignored", func(t *testing.T) {
-		_, err := update("Z", up{y: "test"}, "fsfds")
+		_, err := update(config{dialect: Postgres}, "Z", up{y: "test"}, "fsfds")
 		if !match.MatchString(err.Error()) {
 			t.Fatalf("expected error matching %v, got: %s", match, err)
 		}
@@ -83,7 +84,7 @@ insert into Z values(null, null, null, null, null, null),('test', 'test', 1, 1,
 
 	for i, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u, err := update("Z", tt.u, "rowid = 1")
+			u, err := update(config{dialect: Postgres}, "Z", tt.u, "rowid = 1")
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -136,7 +137,7 @@ func TestUpdateStatementWithWhere(t *testing.T) {
 	type T struct {
 		A int `sql:"a"`
 	}
-	u, err := update("T", T{2}, "a = $1", 1)
+	u, err := update(config{dialect: Postgres}, "T", T{2}, "a = $1", 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -148,3 +149,140 @@ func TestUpdateStatementWithWhere(t *testing.T) {
 		t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
 	}
 }
+
+func TestUpdateContext(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`create table T(a int)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`insert into T values(1)`); err != nil {
+		t.Fatal(err)
+	}
+	type T struct {
+		A int `sql:"a"`
+	}
+	if _, err := UpdateContext(context.Background(), db, "T", T{2}, "a = $1", 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := UpdateContext(ctx, db, "T", T{3}, "a = $1", 2); err != context.Canceled {
+		t.Fatalf("expected %v, got: %v", context.Canceled, err)
+	}
+	var a int
+	if err := Scan(&a, db, `select a from T`); err != nil {
+		t.Fatal(err)
+	}
+	if a != 2 {
+		t.Fatalf("expected 2, got: %d", a)
+	}
+}
+
+func TestUpdateDialect(t *testing.T) {
+	type e struct {
+		A int `sql:"a"`
+	}
+	type T struct {
+		e
+		B int `sql:"b"`
+	}
+	u, err := update(config{dialect: SQLServer}, "T", T{e{1}, 2}, "a = @p1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := preUpdate{
+		statement: `UPDATE T SET a = @p1, b = @p2 WHERE a = @p3`,
+		args:      []interface{}{1, 2, 3},
+	}
+	if !reflect.DeepEqual(exp, *u) {
+		t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
+	}
+}
+
+func TestUpdateZeroValues(t *testing.T) {
+	type T struct {
+		ID     int    `sql:"id,pk"`
+		Name   string `sql:"name"`
+		Count  int    `sql:"count"`
+		Active bool   `sql:"active,always"`
+		Note   string `sql:"note/update"`
+	}
+	c := config{dialect: Postgres}
+
+	tests := []struct {
+		name string
+		m    setMode
+		stmt string
+		args []interface{}
+	}{
+		{"always option", setMode{},
+			"UPDATE T SET name = $1, active = $2 WHERE id = $3",
+			[]interface{}{"x", false, 1}},
+		{"named fields", setMode{fields: []string{"count"}},
+			"UPDATE T SET name = $1, count = $2, active = $3 WHERE id = $4",
+			[]interface{}{"x", 0, false, 1}},
+		{"all fields", setMode{all: true},
+			"UPDATE T SET id = $1, name = $2, count = $3, active = $4 WHERE id = $5",
+			[]interface{}{0, "x", 0, false, 1}},
+		{"all fields but keys", setMode{all: true, skipKeys: true},
+			"UPDATE T SET name = $1, count = $2, active = $3 WHERE id = $4",
+			[]interface{}{"x", 0, false, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := updateWith(c, "T", T{Name: "x"}, tt.m, "id = $1", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exp := preUpdate{statement: tt.stmt, args: tt.args}
+			if !reflect.DeepEqual(exp, *u) {
+				t.Fatalf("expected: %#v\ngot: %#v", exp, *u)
+			}
+		})
+	}
+
+	t.Run("unknown field fails", func(t *testing.T) {
+		_, err := updateWith(c, "T", T{}, setMode{fields: []string{"note"}}, "id = $1", 1)
+		if err == nil || err.Error() != "no field for column note" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("zero values are written", func(t *testing.T) {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.Exec(`create table T(id int, name text, count int, active bool)`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`insert into T values(1, 'x', 5, true)`); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := UpdateFields(db, "T", T{}, []string{"count"}, "id = ?", 1); err != nil {
+			t.Fatal(err)
+		}
+		var got T
+		if err := Scan(&got, db, `select id, name, count, active from T`); err != nil {
+			t.Fatal(err)
+		}
+		exp := T{ID: 1, Name: "x"}
+		if !reflect.DeepEqual(exp, got) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, got)
+		}
+		if _, err := UpdateAllContext(context.Background(), db, "T", T{ID: 1}, "id = ?", 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := Scan(&got, db, `select id, name, count, active from T`); err != nil {
+			t.Fatal(err)
+		}
+		exp = T{ID: 1}
+		if !reflect.DeepEqual(exp, got) {
+			t.Fatalf("expected: %#v\ngot: %#v", exp, got)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package sqlh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Upsert runs an INSERT query like Insert, but rows which conflict
+// with an existing row on the given columns update the existing row
+// instead.
+//
+//   type val struct {
+//     A int `sql:"id"`
+//     B string `sql:"b"`
+//     C time.Time `sql:"created/upsert"`
+//   }
+//   res, err := Upsert(db, "X", val{1, "test", time.Now()}, "id")
+//   // = db.Exec(`insert into X(id, b, created) values($1, $2, $3)
+//   //     on conflict(id) do update set b = excluded.b`, 1, "test", ...)
+//
+// All inserted columns other than the conflict columns are
+// overwritten, except for those ignored in the upsert context, which
+// keep their existing value.
+//
+// With a DuplicateKey dialect such as MySQL, the statement uses ON
+// DUPLICATE KEY UPDATE, and the conflict columns are only needed
+// when there are no other columns to overwrite.
+func Upsert(db Executor, table string, values interface{}, conflict ...string) (sql.Result, error) {
+	u, err := upsert(configOf(db), table, values, conflict)
+	if err != nil {
+		return nil, err
+	}
+	return db.Exec(u.statement, u.args...)
+}
+
+// UpsertContext is like Upsert, but executes the statement with a
+// context.
+func UpsertContext(ctx context.Context, db ExecutorContext, table string, values interface{}, conflict ...string) (sql.Result, error) {
+	u, err := upsert(configOf(db), table, values, conflict)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, u.statement, u.args...)
+}
+
+func upsert(c config, table string, values interface{}, conflict []string) (*preInsert, error) {
+	if len(conflict) < 1 && !c.dialect.DuplicateKey {
+		return nil, fmt.Errorf("no conflict columns given")
+	}
+
+	i, err := insert(c, table, values)
+	if err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(values)
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	// Conflict columns, and those ignored in the upsert context,
+	// keep their existing value. All keys of a map are overwritten.
+	overwrite := i.columns
+	if !isMap(t) {
+		overwrite, _ = taggedFields(c, t, "upsert")
+	}
+	var set []string
+	for _, name := range i.columns {
+		if contains(conflict, name) || !contains(overwrite, name) {
+			continue
+		}
+		if c.dialect.DuplicateKey {
+			set = append(set, fmt.Sprintf("%s = values(%s)", name, name))
+		} else {
+			set = append(set, fmt.Sprintf("%s = excluded.%s", name, name))
+		}
+	}
+
+	var clause string
+	switch {
+	case c.dialect.DuplicateKey && len(set) > 0:
+		clause = " on duplicate key update " + strings.Join(set, ", ")
+	case c.dialect.DuplicateKey && len(conflict) > 0:
+		// MySQL has no DO NOTHING, so set a column to itself
+		clause = fmt.Sprintf(" on duplicate key update %s = %s", conflict[0], conflict[0])
+	case c.dialect.DuplicateKey:
+		return nil, fmt.Errorf("no columns to update on conflict")
+	case len(set) > 0:
+		clause = fmt.Sprintf(" on conflict(%s) do update set %s", strings.Join(conflict, ", "), strings.Join(set, ", "))
+	default:
+		clause = fmt.Sprintf(" on conflict(%s) do nothing", strings.Join(conflict, ", "))
+	}
+
+	i.statement += clause
+	return i, nil
+}
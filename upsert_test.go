@@ -0,0 +1,97 @@
+package sqlh
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestUpsert(t *testing.T) {
+	type e struct {
+		ID int `sql:"id"`
+	}
+	type row struct {
+		e
+		A string `sql:"a"`
+		B string `sql:"b/upsert"` // Kept on conflict
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`create table T(id int primary key, a text, b text)`); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("statement", func(t *testing.T) {
+		u, err := upsert(config{dialect: Postgres}, "T", row{e{1}, "a", "b"}, []string{"id"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into T(id, a, b) values($1, $2, $3) on conflict(id) do update set a = excluded.a`
+		if statement != u.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, u.statement)
+		}
+		if !reflect.DeepEqual([]interface{}{1, "a", "b"}, u.args) {
+			t.Fatalf("unexpected args: %#v", u.args)
+		}
+	})
+
+	t.Run("duplicate key statement", func(t *testing.T) {
+		u, err := upsert(config{dialect: MySQL}, "T", row{e{1}, "a", "b"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into T(id, a, b) values(?, ?, ?) on duplicate key update id = values(id), a = values(a)`
+		if statement != u.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, u.statement)
+		}
+	})
+
+	t.Run("nothing to overwrite", func(t *testing.T) {
+		type row struct {
+			ID int `sql:"id"`
+		}
+		u, err := upsert(config{dialect: Postgres}, "T", row{1}, []string{"id"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement := `insert into T(id) values($1) on conflict(id) do nothing`
+		if statement != u.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, u.statement)
+		}
+		u, err = upsert(config{dialect: MySQL}, "T", row{1}, []string{"id"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		statement = `insert into T(id) values(?) on duplicate key update id = id`
+		if statement != u.statement {
+			t.Fatalf("expected: %#v, got: %#v", statement, u.statement)
+		}
+	})
+
+	t.Run("conflict columns required", func(t *testing.T) {
+		if _, err := upsert(config{dialect: Postgres}, "T", row{}, nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		rows := []row{{e{1}, "a", "b"}, {e{2}, "a", "b"}}
+		if _, err := Upsert(db, "T", rows, "id"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Upsert(db, "T", row{e{1}, "updated", "updated"}, "id"); err != nil {
+			t.Fatal(err)
+		}
+		var dest []row
+		if err := Scan(&dest, db, `select * from T order by id`); err != nil {
+			t.Fatal(err)
+		}
+		expect := []row{{e{1}, "updated", "b"}, {e{2}, "a", "b"}}
+		if !reflect.DeepEqual(expect, dest) {
+			t.Fatalf("expected: %#v\ngot: %#v", expect, dest)
+		}
+	})
+}